@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"goak/internal/goak"
-	"goak/internal/goak/colors"
 	"goak/internal/goak/components"
 	"goak/internal/goak/layout"
 )
@@ -28,24 +27,54 @@ func buildUI() *components.UI {
 
 	mainMenu := root.CreateMenuBar(layout.StaticPx(28), components.MenuBarWidthFull)
 	mainMenu.
-		AddItem("File", nil).
-		AddSubItem("New", func() { fmt.Println("File -> New") }).
-		AddSubItem("Open", func() { fmt.Println("File -> Open") }).
+		AddItem("&File", nil).
+		AddEntry(components.MenuEntry{Label: "&New", Shortcut: "Ctrl+N", OnClick: func() { fmt.Println("File -> New") }}).
+		AddEntry(components.MenuEntry{Label: "&Open", Shortcut: "Ctrl+O", OnClick: func() { fmt.Println("File -> Open") }}).
+		AddSubMenu("&Recent", func(recent *components.MenuEntry) {
+			recent.
+				AddSubItem("project.go", func() { fmt.Println("File -> Recent -> project.go") }).
+				AddSubItem("notes.txt", func() { fmt.Println("File -> Recent -> notes.txt") }).
+				AddSeparator().
+				AddSubMenu("Archived", func(archived *components.MenuEntry) {
+					archived.AddSubItem("old.go", func() { fmt.Println("File -> Recent -> Archived -> old.go") })
+				})
+		}).
 		AddSeparator().
-		AddSubItem("Exit", func() { fmt.Println("File -> Exit") })
+		AddSubItem("E&xit", func() { fmt.Println("File -> Exit") })
 	mainMenu.
-		AddItem("Edit", nil).
-		AddSubItem("Cut", func() { fmt.Println("Edit -> Cut") }).
-		AddSubItem("Copy", func() { fmt.Println("Edit -> Copy") }).
-		AddSubItem("Paste", func() { fmt.Println("Edit -> Paste") })
-	mainMenu.AddItem("Help", func() { fmt.Println("Help clicked") })
-
-	container := root.CreatePanel(layout.PercentOf(100), layout.PercentOf(100))
-	container.SetBackground(colors.HexOr("#1e1e1e", colors.RGB(30, 30, 30)))
+		AddItem("&Edit", nil).
+		AddEntry(components.MenuEntry{Label: "&Undo", Shortcut: "Ctrl+Z", Disabled: true}).
+		AddSeparator().
+		AddSubItem("Cu&t", func() { fmt.Println("Edit -> Cut") }).
+		AddSubItem("&Copy", func() { fmt.Println("Edit -> Copy") }).
+		AddSubItem("&Paste", func() { fmt.Println("Edit -> Paste") })
+
+	container := root.CreateScrollPanel(layout.PercentOf(100), layout.AutoSize())
+	setTheme := func(theme components.Theme) {
+		ui.SetTheme(theme)
+		container.SetBackground(theme.Background)
+	}
+	setTheme(components.DarkTheme())
+	mainMenu.
+		AddItem("&View", nil).
+		AddEntry(components.MenuEntry{Kind: components.MenuEntryRadio, Group: "theme", Label: "Dark theme", Checked: true, OnClick: func() { setTheme(components.DarkTheme()) }}).
+		AddEntry(components.MenuEntry{Kind: components.MenuEntryRadio, Group: "theme", Label: "Light theme", OnClick: func() { setTheme(components.LightTheme()) }}).
+		AddSeparator().
+		AddEntry(components.MenuEntry{
+			Kind:     components.MenuEntryCheck,
+			Label:    "Word wrap",
+			Shortcut: "Alt+Z",
+			Checked:  true,
+			OnCheck:  func(checked bool) { fmt.Println("View -> Word wrap:", checked) },
+		})
+	mainMenu.AddItem("&Help", func() { fmt.Println("Help clicked") })
+
 	container.SetAlignment(layout.AlignCenter, layout.AlignCenter)
 
+	heading := container.CreateLabel(layout.PercentOf(95), layout.FitSize(), "Widget showcase")
+	heading.Font = &components.Font{Size: 28, Weight: components.WeightBold}
+
 	buttonSection := container.CreatePanel(layout.PercentOf(95), layout.StaticPx(100))
-	buttonSection.SetBackground(colors.HexOr("#2d2d2d", colors.RGB(45, 45, 45)))
 	buttonSection.SetAlignment(layout.AlignStart, layout.AlignCenter)
 
 	btn1 := buttonSection.CreateButton(layout.StaticPx(120), layout.StaticPx(32), "Click Me!")
@@ -55,7 +84,6 @@ func buildUI() *components.UI {
 	btn2.OnClick = func() { fmt.Println("Button 2 clicked") }
 
 	checkboxSection := container.CreatePanel(layout.PercentOf(95), layout.StaticPx(110))
-	checkboxSection.SetBackground(colors.HexOr("#2d2d2d", colors.RGB(45, 45, 45)))
 	checkboxSection.SetAlignment(layout.AlignStart, layout.AlignStart)
 
 	cb1 := checkboxSection.CreateCheckbox(layout.StaticPx(200), layout.StaticPx(24), "Enable feature A")
@@ -73,9 +101,14 @@ func buildUI() *components.UI {
 	cb3.OnChanged = func(checked bool) {
 		fmt.Printf("Feature C: %v\n", checked)
 	}
+	// Clicks on the checkboxes bubble up to their section.
+	checkboxSection.On(components.EventMouseDown, func(e *components.Event) {
+		if e.Phase == components.PhaseBubble {
+			fmt.Println("Checkbox section: child clicked")
+		}
+	})
 
 	radioSection := container.CreatePanel(layout.PercentOf(95), layout.StaticPx(120))
-	radioSection.SetBackground(colors.HexOr("#2d2d2d", colors.RGB(45, 45, 45)))
 	radioSection.SetAlignment(layout.AlignStart, layout.AlignStart)
 
 	radioOptions := []components.RadioOption{
@@ -91,7 +124,6 @@ func buildUI() *components.UI {
 	}
 
 	sliderSection := container.CreatePanel(layout.PercentOf(95), layout.StaticPx(80))
-	sliderSection.SetBackground(colors.HexOr("#2d2d2d", colors.RGB(45, 45, 45)))
 	sliderSection.SetAlignment(layout.AlignStart, layout.AlignStart)
 
 	slider := sliderSection.CreateSlider(layout.StaticPx(400), layout.StaticPx(60), "Volume", 0, 100, 50)
@@ -100,7 +132,6 @@ func buildUI() *components.UI {
 	}
 
 	dropdownSection := container.CreatePanel(layout.PercentOf(95), layout.StaticPx(80))
-	dropdownSection.SetBackground(colors.HexOr("#2d2d2d", colors.RGB(45, 45, 45)))
 	dropdownSection.SetAlignment(layout.AlignStart, layout.AlignCenter)
 
 	dropdownOptions := []components.DropdownOption{
@@ -115,24 +146,63 @@ func buildUI() *components.UI {
 		fmt.Printf("Dropdown selected: %s (index %d)\n", value, index)
 	}
 
+	inputSection := container.CreatePanel(layout.PercentOf(95), layout.StaticPx(60))
+	inputSection.SetAlignment(layout.AlignStart, layout.AlignCenter)
+	inputSection.SetDirection(layout.LeftToRight)
+	inputSection.SetGap(10)
+
+	nameInput := inputSection.CreateTextInput(layout.StaticPx(220), layout.StaticPx(32), "Your name")
+	nameInput.MaxLength = 32
+	nameInput.OnSubmit = func(text string) {
+		fmt.Printf("Name submitted: %s\n", text)
+	}
+
+	passwordInput := inputSection.CreateTextInput(layout.StaticPx(220), layout.StaticPx(32), "Password")
+	passwordInput.Password = true
+	passwordInput.OnChanged = func(text string) {
+		fmt.Printf("Password length: %d\n", len([]rune(text)))
+	}
+
+	notesSection := container.CreatePanel(layout.PercentOf(95), layout.StaticPx(150))
+	notesSection.SetAlignment(layout.AlignStart, layout.AlignCenter)
+
+	notes := notesSection.CreateTextArea(layout.PercentOf(100), layout.StaticPx(130))
+	notes.SetText("Notes wrap at the edge of the area.\nUse the arrow keys, Page Up/Down and the mouse wheel to move around.")
+
+	accel := ui.Accelerators()
+	accel.Register(components.Command{Name: "demo.saveAll", Run: func() { fmt.Println("Accelerator: Save all") }}, "Ctrl+K Ctrl+S")
+	accel.Register(components.Command{Name: "demo.clearNotes", Run: func() { notes.SetText("") }})
+	if err := accel.Bind("Ctrl+L", "demo.clearNotes", notes); err != nil {
+		fmt.Println(err)
+	}
+	// Ctrl+D toggles the debug overlay instead of F12.
+	accel.UnbindCommand(goak.CommandToggleDebug)
+	if err := accel.Bind("Ctrl+D", goak.CommandToggleDebug, nil); err != nil {
+		fmt.Println(err)
+	}
+
 	contextMenu := components.NewContextMenu([]components.ContextMenuItem{
 		{Kind: components.ContextMenuItemAction, Label: "Copy", OnClick: func() { fmt.Println("Context: Copy") }},
 		{Kind: components.ContextMenuItemAction, Label: "Paste", OnClick: func() { fmt.Println("Context: Paste") }},
+		{Kind: components.ContextMenuItemAction, Label: "Share", SubItems: []components.ContextMenuItem{
+			{Kind: components.ContextMenuItemAction, Label: "Email", OnClick: func() { fmt.Println("Context: Share -> Email") }},
+			{Kind: components.ContextMenuItemAction, Label: "Link", OnClick: func() { fmt.Println("Context: Share -> Link") }},
+		}},
 		{Kind: components.ContextMenuItemSeparator},
-		{Kind: components.ContextMenuItemAction, Label: "Delete", OnClick: func() { fmt.Println("Context: Delete") }},
+		{Kind: components.ContextMenuItemCheck, Label: "Pinned", OnCheck: func(checked bool) { fmt.Println("Context: Pinned", checked) }},
+		{Kind: components.ContextMenuItemAction, Label: "Delete", Shortcut: "Shift+Del", OnClick: func() { fmt.Println("Context: Delete") }},
 		{Kind: components.ContextMenuItemAction, Label: "Properties", OnClick: func() { fmt.Println("Context: Properties") }},
 	})
-	container.AddContextMenu(contextMenu)
+	container.SetContextMenu(contextMenu)
 
 	infoSection := container.CreatePanel(layout.PercentOf(95), layout.StaticPx(60))
-	infoSection.SetBackground(colors.HexOr("#252525", colors.RGB(37, 37, 37)))
 	infoSection.SetAlignment(layout.AlignCenter, layout.AlignCenter)
 
 	infoBtn := infoSection.CreateButton(layout.StaticPx(250), layout.StaticPx(36), "Demo")
 	infoBtn.OnClick = func() {
 		fmt.Println("This demo shows all available components:")
 		fmt.Println("- Buttons, Checkboxes, Radio Groups")
-		fmt.Println("- Sliders, Dropdowns, Context Menus")
+		fmt.Println("- Sliders, Dropdowns, Text Inputs, Context Menus")
 		fmt.Println("- Menu Bars with submenus")
 		fmt.Println("Try Ctrl+/- to scale the UI!")
 	}
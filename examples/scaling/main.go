@@ -53,8 +53,9 @@ func buildUI() *components.UI {
 	content.SetBackground(colors.DarkBlue)
 	content.SetAlignment(layout.AlignCenter, layout.AlignCenter)
 
-	info := content.CreateButton(layout.StaticPx(500), layout.StaticPx(42), "Press Ctrl + / Ctrl - to scale the whole app")
-	info.OnClick = func() { fmt.Println("hint: use keyboard shortcuts") }
+	info := content.CreateLabel(layout.StaticPx(500), layout.FitSize(), "Press Ctrl + / Ctrl - to scale the whole app")
+	info.SetAlignment(layout.AlignCenter, layout.AlignCenter)
+	info.SetWrap(true)
 
 	return ui
 }
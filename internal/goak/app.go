@@ -71,6 +71,15 @@ func (a *App) Destroy() {
 	}
 }
 
+// Fonts returns the font registry of the window, or nil before InitWindow.
+// Load custom fonts on it before Run, e.g. app.Fonts().LoadFile("Mono", components.WeightRegular, components.StyleNormal, "mono.ttf").
+func (a *App) Fonts() *components.FontRegistry {
+	if a.win == nil {
+		return nil
+	}
+	return a.win.Fonts()
+}
+
 // Window returns the window handle.
 func (a *App) Window() *Window {
 	return a.win
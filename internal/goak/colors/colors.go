@@ -1,6 +1,7 @@
 package colors
 
 import (
+	"fmt"
 	"image/color"
 	"strconv"
 	"strings"
@@ -42,26 +43,39 @@ func (c Color) NRGBA() color.NRGBA {
 	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}
 }
 
-// ParseHex parses #RGB or #RRGGBB colors.
+// ParseHex parses #RGB, #RGBA, #RRGGBB or #RRGGBBAA colors.
 func ParseHex(s string) (Color, bool) {
 	h := strings.TrimSpace(strings.TrimPrefix(s, "#"))
-	switch len(h) {
-	case 3:
-		r, errR := strconv.ParseUint(strings.Repeat(string(h[0]), 2), 16, 8)
-		g, errG := strconv.ParseUint(strings.Repeat(string(h[1]), 2), 16, 8)
-		b, errB := strconv.ParseUint(strings.Repeat(string(h[2]), 2), 16, 8)
-		if errR == nil && errG == nil && errB == nil {
-			return RGB(uint8(r), uint8(g), uint8(b)), true
+	if len(h) == 3 || len(h) == 4 {
+		var long strings.Builder
+		for i := range len(h) {
+			long.WriteString(strings.Repeat(string(h[i]), 2))
 		}
+		h = long.String()
+	}
+	switch len(h) {
 	case 6:
 		v, err := strconv.ParseUint(h, 16, 32)
 		if err == nil {
 			return RGB(uint8(v>>16), uint8(v>>8), uint8(v)), true
 		}
+	case 8:
+		v, err := strconv.ParseUint(h, 16, 32)
+		if err == nil {
+			return RGBA(uint8(v>>24), uint8(v>>16), uint8(v>>8), uint8(v)), true
+		}
 	}
 	return Color{}, false
 }
 
+// Hex formats the color as #RRGGBB, or #RRGGBBAA when it is not opaque.
+func (c Color) Hex() string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
 // HexOr parses a hex color and falls back if invalid.
 func HexOr(s string, fallback Color) Color {
 	if c, ok := ParseHex(s); ok {
@@ -0,0 +1,285 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Accelerator is a sequence of shortcuts pressed one after the other: one for a plain
+// chord such as Ctrl+S, more for a multi-stroke sequence such as Ctrl+K Ctrl+S.
+type Accelerator []Shortcut
+
+// ParseAccelerator parses shortcuts separated by spaces, e.g. "Ctrl+K Ctrl+S" (see
+// ParseShortcut).
+func ParseAccelerator(s string) (Accelerator, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, errors.New("empty accelerator")
+	}
+	a := make(Accelerator, len(fields))
+	for i, f := range fields {
+		sc, err := ParseShortcut(f)
+		if err != nil {
+			return nil, err
+		}
+		a[i] = sc
+	}
+	return a, nil
+}
+
+// String formats the accelerator as ParseAccelerator reads it.
+func (a Accelerator) String() string {
+	parts := make([]string, len(a))
+	for i, s := range a {
+		parts[i] = s.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// startsWith reports whether the first strokes of a are prefix.
+func (a Accelerator) startsWith(prefix Accelerator) bool {
+	return len(prefix) <= len(a) && slices.Equal(a[:len(prefix)], prefix)
+}
+
+// Command is a named action that accelerators run, e.g. "window.zoomIn".
+type Command struct {
+	Name    string
+	Run     func()
+	Enabled func() bool // nil means always; the keys of a disabled command go on to the UI
+}
+
+// Binding binds an accelerator to a command by name.
+type Binding struct {
+	Keys    Accelerator
+	Command string
+	Scope   Widget // nil for the whole UI, else only while focus is inside Scope
+}
+
+// ConflictError reports a binding whose keys clash with a binding of the same scope: both
+// have the same keys, or the keys of one start the other, which could then never run.
+type ConflictError struct {
+	Binding  Binding
+	Existing Binding
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("accelerator %s for %q conflicts with %s for %q",
+		e.Binding.Keys, e.Binding.Command, e.Existing.Keys, e.Existing.Command)
+}
+
+// Accelerators is the keyboard accelerator registry of a UI (see UI.Accelerators). It maps
+// accelerators to commands; bindings and commands are matched by name, so either can be
+// added first.
+//
+// A key press the focused widget does not handle starts an accelerator: the command
+// bound to it runs, or, when it is the first stroke of longer accelerators, the next
+// presses go to the registry before any widget until the sequence completes or matches
+// nothing. A binding with a Scope applies only while focus is inside the scope and wins
+// over bindings of enclosing scopes and global ones with the same keys. Menus bind the
+// Shortcut of their entries here too; an entry shortcut that conflicts with an earlier
+// binding is left out until that binding is removed.
+type Accelerators struct {
+	commands    map[string]Command
+	bindings    []Binding
+	customized  map[string]bool // commands bound or unbound by the application
+	pending     Accelerator     // strokes of a sequence typed so far
+	swallowText bool            // the last key press was used; drop the text it typed
+	generation  int             // bumped whenever bindings change
+}
+
+func newAccelerators() *Accelerators {
+	return &Accelerators{
+		commands:   make(map[string]Command),
+		customized: make(map[string]bool),
+	}
+}
+
+// Register adds a command, replacing an earlier one with the same name. Unless the
+// bindings of the command were already changed with Bind, Unbind or UnbindCommand, it is
+// bound globally to the default accelerators, so applications can rebind or disable
+// built-in commands before or after they are registered.
+func (a *Accelerators) Register(c Command, defaults ...string) error {
+	a.commands[c.Name] = c
+	if a.customized[c.Name] {
+		return nil
+	}
+	for _, s := range defaults {
+		keys, err := ParseAccelerator(s)
+		if err != nil {
+			return err
+		}
+		if err := a.bind(Binding{Keys: keys, Command: c.Name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bind binds keys to a command, only while focus is inside scope unless scope is nil. It
+// returns a *ConflictError when the keys clash with a binding of the same scope.
+func (a *Accelerators) Bind(keys string, command string, scope Widget) error {
+	k, err := ParseAccelerator(keys)
+	if err != nil {
+		return err
+	}
+	a.customized[command] = true
+	return a.bind(Binding{Keys: k, Command: command, Scope: scope})
+}
+
+func (a *Accelerators) bind(b Binding) error {
+	for _, old := range a.bindings {
+		if old.Scope == b.Scope && (old.Keys.startsWith(b.Keys) || b.Keys.startsWith(old.Keys)) {
+			return &ConflictError{Binding: b, Existing: old}
+		}
+	}
+	a.bindings = append(a.bindings, b)
+	a.generation++
+	return nil
+}
+
+// Unbind removes the binding of keys in scope, if any.
+func (a *Accelerators) Unbind(keys string, scope Widget) error {
+	k, err := ParseAccelerator(keys)
+	if err != nil {
+		return err
+	}
+	a.unbind(k, scope, true)
+	return nil
+}
+
+func (a *Accelerators) unbind(keys Accelerator, scope Widget, customize bool) {
+	a.deleteBindings(func(b Binding) bool {
+		if b.Scope != scope || !slices.Equal(b.Keys, keys) {
+			return false
+		}
+		if customize {
+			a.customized[b.Command] = true
+		}
+		return true
+	})
+}
+
+// UnbindCommand removes every binding of a command, which disables its accelerators.
+func (a *Accelerators) UnbindCommand(command string) {
+	a.customized[command] = true
+	a.deleteBindings(func(b Binding) bool { return b.Command == command })
+}
+
+// removeCommand removes a command and its bindings, without marking it as changed by the
+// application.
+func (a *Accelerators) removeCommand(name string) {
+	delete(a.commands, name)
+	a.deleteBindings(func(b Binding) bool { return b.Command == name })
+}
+
+// deleteBindings removes the bindings del reports.
+func (a *Accelerators) deleteBindings(del func(b Binding) bool) {
+	n := len(a.bindings)
+	a.bindings = slices.DeleteFunc(a.bindings, del)
+	if len(a.bindings) != n {
+		a.generation++
+	}
+}
+
+// Bindings returns the bindings in the order they were added.
+func (a *Accelerators) Bindings() []Binding {
+	return slices.Clone(a.bindings)
+}
+
+// KeysFor returns the accelerators bound to a command, e.g. to show them in a menu.
+func (a *Accelerators) KeysFor(command string) []Accelerator {
+	var keys []Accelerator
+	for _, b := range a.bindings {
+		if b.Command == command {
+			keys = append(keys, b.Keys)
+		}
+	}
+	return keys
+}
+
+// Pending returns the strokes typed so far of an unfinished sequence, or nil.
+func (a *Accelerators) Pending() Accelerator {
+	return slices.Clone(a.pending)
+}
+
+// enabled reports whether a command is registered and enabled.
+func (a *Accelerators) enabled(name string) bool {
+	c, ok := a.commands[name]
+	return ok && c.Run != nil && (c.Enabled == nil || c.Enabled())
+}
+
+// handle runs the command bound to the sequence the key press in e completes, or keeps
+// e as the next stroke of a longer sequence. It reports whether e was used; a press that
+// ends a started sequence without matching is used too, so it reaches no widget.
+func (a *Accelerators) handle(e *Event, focus Widget) bool {
+	if e.Repeat || isModifierKey(e.Key) {
+		return false
+	}
+	seq := append(a.pending, Shortcut{Key: e.Key, Mods: e.Mods})
+	started := len(a.pending) > 0
+	a.pending = nil
+
+	exact, exactDepth, prefixDepth := "", -1, -1
+	for _, b := range a.bindings {
+		depth, ok := scopeDepth(b.Scope, focus)
+		if !ok || !a.enabled(b.Command) {
+			continue
+		}
+		switch {
+		case slices.Equal(b.Keys, seq):
+			if depth > exactDepth {
+				exact, exactDepth = b.Command, depth
+			}
+		case b.Keys.startsWith(seq):
+			prefixDepth = max(prefixDepth, depth)
+		}
+	}
+	switch {
+	case exactDepth >= 0 && exactDepth >= prefixDepth:
+		a.commands[exact].Run()
+	case prefixDepth >= 0:
+		a.pending = seq
+	default:
+		a.swallowText = started
+		return started
+	}
+	a.swallowText = true
+	return true
+}
+
+// scopeDepth reports whether a binding scope applies with focus, and how deep the scope
+// is: 0 for nil (global), else one more than the number of its ancestors.
+func scopeDepth(scope Widget, focus Widget) (int, bool) {
+	if scope == nil {
+		return 0, true
+	}
+	if focus == nil || !scope.element().contains(focus) {
+		return 0, false
+	}
+	depth := 0
+	for cur := scope.element(); cur != nil; cur = cur.parent {
+		depth++
+	}
+	return depth, true
+}
+
+// isModifierKey reports whether k is a modifier key, which does not end a sequence.
+func isModifierKey(k ebiten.Key) bool {
+	switch k {
+	case ebiten.KeyShift, ebiten.KeyShiftLeft, ebiten.KeyShiftRight,
+		ebiten.KeyControl, ebiten.KeyControlLeft, ebiten.KeyControlRight,
+		ebiten.KeyAlt, ebiten.KeyAltLeft, ebiten.KeyAltRight,
+		ebiten.KeyMeta, ebiten.KeyMetaLeft, ebiten.KeyMetaRight:
+		return true
+	}
+	return false
+}
+
+// Accelerators returns the accelerator registry of the UI.
+func (u *UI) Accelerators() *Accelerators {
+	return u.accel
+}
@@ -12,18 +12,32 @@ import (
 // Button is a clickable control with a label.
 // Create with NewButton for reuse; add with panel.AddButton(btn) and set OnClick per instance.
 type Button struct {
-	c       *layout.Container
+	Element
 	Label   string
+	Font    *Font        // nil uses the UI default font
+	Theme   *ButtonTheme // nil uses the UI theme
 	OnClick func()
 }
 
 // NewButton creates a standalone button (not in the tree). Add it with panel.AddButton(btn), then set OnClick.
 func NewButton(width, height layout.Size, label string) *Button {
-	return &Button{c: layout.NewContainer(width, height), Label: label}
+	b := &Button{Label: label}
+	b.Init(layout.NewContainer(width, height))
+	b.SetFocusable(true)
+	b.c.Measure = b.measure
+	return b
 }
 
-// Bounds returns the computed layout rect after Layout.
-func (b *Button) Bounds() layout.Rect { return b.c.Bounds }
+const (
+	buttonPaddingX = 12.0
+	buttonPaddingY = 6.0
+)
+
+// measure reports the label size plus padding for Fit sizing.
+func (b *Button) measure(float64) (float64, float64) {
+	tw, th := measureText(b.ui, b.Font, b.Label)
+	return tw + buttonPaddingX*2, th + buttonPaddingY*2
+}
 
 // ButtonTheme controls button drawing colors.
 type ButtonTheme struct {
@@ -41,14 +55,34 @@ func DefaultButtonTheme() ButtonTheme {
 	}
 }
 
-func (b *Button) Draw(dst *ebiten.Image, face text.GoTextFace, theme ButtonTheme) {
+// Draw draws the button box and its centered label.
+func (b *Button) Draw(ctx *DrawContext) {
+	b.draw(ctx.Dst, ctx.FaceFor(b.Font), themeOr(b.Theme, ctx.Theme.Button))
+}
+
+func (b *Button) draw(dst *ebiten.Image, face text.Face, theme ButtonTheme) {
 	bound := b.Bounds()
 	rendering.FillRect(dst, bound.X, bound.Y, bound.W, bound.H, theme.Fill)
 	rendering.DrawStrokeRect(dst, bound.X, bound.Y, bound.W, bound.H, 1.0, theme.Stroke)
 
-	tw, th := text.Measure(b.Label, &face, 0)
+	tw, th := text.Measure(b.Label, face, 0)
 	tx := bound.X + (bound.W-tw)/2
 	ty := bound.Y + (bound.H-th)/2
 
 	rendering.DrawText(dst, b.Label, face, int(tx), int(ty), theme.Text)
 }
+
+// HandleEvent runs OnClick when the button is pressed, or activated with Space or Enter
+// while focused.
+func (b *Button) HandleEvent(e *Event) {
+	switch {
+	case e.isPrimaryPress():
+	case isActivation(e):
+		e.StopPropagation()
+	default:
+		return
+	}
+	if b.OnClick != nil {
+		b.OnClick()
+	}
+}
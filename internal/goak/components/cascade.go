@@ -0,0 +1,615 @@
+package components
+
+import (
+	"goak/internal/goak/colors"
+	"goak/internal/goak/layout"
+	"goak/internal/goak/rendering"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// menuSubmenuDelay is how many ticks (300ms at 60 ticks per second) the pointer rests on
+// a row before its submenu opens, or before the submenu of another row closes.
+const menuSubmenuDelay = 18
+
+// menuMark is the mark drawn before the label of a checkable row.
+type menuMark int
+
+const (
+	menuMarkNone menuMark = iota
+	menuMarkCheck
+	menuMarkRadio
+)
+
+// menuRow describes one row of a menu level to a menuCascade.
+type menuRow struct {
+	separator bool
+	disabled  bool
+	submenu   bool   // the row opens a submenu instead of running an action
+	label     string // without the mnemonic marker
+	mnemonic  int    // byte index in label of the mnemonic character, or -1
+	shortcut  string
+	icon      *ebiten.Image
+	mark      menuMark
+	checked   bool
+}
+
+// parseMnemonic removes the mnemonic marker from a label: "&" marks the character after it
+// as the mnemonic, as in "&File" or "Save &As", and "&&" stands for "&". It returns the
+// label to draw and the byte index of the mnemonic in it, or -1 for none.
+func parseMnemonic(label string) (string, int) {
+	if !strings.Contains(label, "&") {
+		return label, -1
+	}
+	var b strings.Builder
+	index := -1
+	for i := 0; i < len(label); i++ {
+		if label[i] == '&' && i+1 < len(label) {
+			i++
+			if label[i] != '&' && index < 0 {
+				index = b.Len()
+			}
+		}
+		b.WriteByte(label[i])
+	}
+	return b.String(), index
+}
+
+// matchesKey reports whether typing r selects the row: r is its mnemonic or, when mnemonic
+// is false, the first letter of its label. Case does not matter.
+func (r menuRow) matchesKey(key rune, mnemonic bool) bool {
+	i := 0
+	if mnemonic {
+		if r.mnemonic < 0 {
+			return false
+		}
+		i = r.mnemonic
+	}
+	first, _ := utf8.DecodeRuneInString(r.label[i:])
+	return r.label != "" && unicode.ToLower(first) == key
+}
+
+// menuMatches returns the selectable rows that typing key selects: the rows with key as
+// their mnemonic or, when no row has it, the rows whose label starts with key.
+func menuMatches(rows []menuRow, key rune) []int {
+	for _, mnemonic := range []bool{true, false} {
+		var matches []int
+		for i, r := range rows {
+			if r.selectable() && r.matchesKey(key, mnemonic) {
+				matches = append(matches, i)
+			}
+		}
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+	return nil
+}
+
+// nextMatch returns the first of matches after current, wrapping around.
+func nextMatch(matches []int, current int) int {
+	for _, i := range matches {
+		if i > current {
+			return i
+		}
+	}
+	return matches[0]
+}
+
+// keyRune returns the lower-case letter or digit typed with k, or 0.
+func keyRune(k ebiten.Key) rune {
+	switch {
+	case k >= ebiten.KeyA && k <= ebiten.KeyZ:
+		return 'a' + rune(k-ebiten.KeyA)
+	case k >= ebiten.KeyDigit0 && k <= ebiten.KeyDigit9:
+		return '0' + rune(k-ebiten.KeyDigit0)
+	}
+	return 0
+}
+
+// isTypeahead reports whether e types a letter or digit for typeahead in an open menu:
+// the key alone, or with Shift or Alt.
+func isTypeahead(e *Event) bool {
+	return !e.Repeat && keyRune(e.Key) != 0 && e.Mods&^(ModShift|ModAlt) == 0
+}
+
+// mnemonicOf returns the lower-case mnemonic character of a label (see parseMnemonic), or
+// 0 when it has none.
+func mnemonicOf(label string) rune {
+	for i := 0; i < len(label)-1; i++ {
+		if label[i] != '&' {
+			continue
+		}
+		i++
+		if label[i] != '&' {
+			r, _ := utf8.DecodeRuneInString(label[i:])
+			return unicode.ToLower(r)
+		}
+	}
+	return 0
+}
+
+// runeKey returns the key that types the lower-case letter or digit r (see keyRune).
+func runeKey(r rune) (ebiten.Key, bool) {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return ebiten.KeyA + ebiten.Key(r-'a'), true
+	case r >= '0' && r <= '9':
+		return ebiten.KeyDigit0 + ebiten.Key(r-'0'), true
+	}
+	return 0, false
+}
+
+// selectable reports whether the row can be highlighted.
+func (r menuRow) selectable() bool {
+	return !r.separator && !r.disabled
+}
+
+// menuCascade tracks the open levels of a menu with submenus, shared by the MenuBar
+// dropdowns and ContextMenu. Level 0 is the list the owner opens; each deeper level is the
+// submenu of a row of the level above and floats beside that row, flipped to the left
+// when it would overflow the window. The owner supplies the rows, row heights and widths,
+// and draws the levels.
+type menuCascade struct {
+	levels []*layout.Container // layout node of each level; levels[0] belongs to the owner
+	path   []int               // row whose submenu is open, for every level but the deepest
+	hover  int                 // highlighted row of the deepest level, or -1
+
+	pendingLevel int // level of the row waiting for menuSubmenuDelay, or -1
+	pendingRow   int
+	pendingTicks int // ticks the pointer has rested on the pending row
+	lastX, lastY float64
+
+	rows      func(path []int) []menuRow // rows of the level reached through path
+	rowHeight func(r menuRow) float64
+	width     func(path []int) float64
+}
+
+// newMenuCascade returns a cascade whose level 0 is laid out by first.
+func newMenuCascade(first *layout.Container, rows func(path []int) []menuRow, rowHeight func(r menuRow) float64, width func(path []int) float64) *menuCascade {
+	return &menuCascade{
+		levels:       []*layout.Container{first},
+		hover:        -1,
+		pendingLevel: -1,
+		rows:         rows,
+		rowHeight:    rowHeight,
+		width:        width,
+	}
+}
+
+// reset closes every submenu and clears the highlight.
+func (c *menuCascade) reset() {
+	c.path = c.path[:0]
+	c.hover = -1
+	c.pendingLevel = -1
+}
+
+// depth returns the number of open levels.
+func (c *menuCascade) depth() int {
+	return len(c.path) + 1
+}
+
+// rowsAt returns the rows of an open level.
+func (c *menuCascade) rowsAt(level int) []menuRow {
+	return c.rows(c.path[:level])
+}
+
+// active returns the highlighted row of a level: the row whose submenu is open, or the
+// hovered row of the deepest level. -1 means none.
+func (c *menuCascade) active(level int) int {
+	if level < len(c.path) {
+		return c.path[level]
+	}
+	return c.hover
+}
+
+// measure returns the size of a level, or zero while it is closed.
+func (c *menuCascade) measure(level int) (float64, float64) {
+	if level > len(c.path) {
+		return 0, 0
+	}
+	rows := c.rowsAt(level)
+	if len(rows) == 0 {
+		return 0, 0
+	}
+	h := 0.0
+	for _, r := range rows {
+		h += c.rowHeight(r)
+	}
+	return c.width(c.path[:level]), h
+}
+
+// sync lines up each submenu with the row that opened it. Call it before layout.
+func (c *menuCascade) sync() {
+	for level := 1; level <= len(c.path); level++ {
+		y := 0.0
+		for _, r := range c.rowsAt(level - 1)[:c.path[level-1]] {
+			y += c.rowHeight(r)
+		}
+		c.levels[level].Floating.OffsetY = y
+	}
+}
+
+// bounds returns the laid out bounds of an open level.
+func (c *menuCascade) bounds(level int) layout.Rect {
+	return c.levels[level].Bounds
+}
+
+// rowRects returns the bounds of every row of an open level.
+func (c *menuCascade) rowRects(level int) []layout.Rect {
+	b := c.bounds(level)
+	rows := c.rowsAt(level)
+	rects := make([]layout.Rect, 0, len(rows))
+	y := b.Y
+	for _, r := range rows {
+		h := c.rowHeight(r)
+		rects = append(rects, layout.Rect{X: b.X, Y: y, W: b.W, H: h})
+		y += h
+	}
+	return rects
+}
+
+// hit returns the level and row at the point, deepest level first, or -1, -1.
+func (c *menuCascade) hit(x, y float64) (level, row int) {
+	for level := len(c.path); level >= 0; level-- {
+		for i, r := range c.rowRects(level) {
+			if rendering.PointWithinBounds(x, y, r) {
+				return level, i
+			}
+		}
+	}
+	return -1, -1
+}
+
+// contains reports whether the point is on any open level.
+func (c *menuCascade) contains(x, y float64) bool {
+	level, _ := c.hit(x, y)
+	return level >= 0
+}
+
+// open opens the submenu of a row, closing the submenus below its level.
+func (c *menuCascade) open(level, row int) {
+	c.path = append(c.path[:level], row)
+	c.hover = -1
+	c.pendingLevel = -1
+	for len(c.levels) <= len(c.path) {
+		c.addLevel()
+	}
+}
+
+// addLevel creates the layout node of the next level, floating beside its parent level.
+func (c *menuCascade) addLevel() {
+	level := len(c.levels)
+	node := layout.NewContainer(layout.FitSize(), layout.FitSize())
+	node.Floating = &layout.Floating{
+		Element:         layout.AttachTopLeft,
+		Target:          layout.AttachTopRight,
+		ClampToViewport: true,
+		FlipX:           true,
+	}
+	node.Measure = func(float64) (float64, float64) { return c.measure(level) }
+	parent := c.levels[level-1]
+	parent.Children = append(parent.Children, node)
+	c.levels = append(c.levels, node)
+}
+
+// pointerMove highlights the row under the pointer. Resting on a row with a submenu opens
+// it after menuSubmenuDelay; resting on another row of a parent level closes the submenus
+// opened from that level after the same delay. It is called once per tick, also when the
+// pointer did not move, and counts the ticks of the delay.
+func (c *menuCascade) pointerMove(x, y float64) {
+	if x != c.lastX || y != c.lastY {
+		c.lastX, c.lastY = x, y
+		c.track(x, y)
+	}
+	if c.pendingLevel < 0 {
+		return
+	}
+	c.pendingTicks++
+	if c.pendingTicks >= menuSubmenuDelay {
+		c.openPending()
+	}
+}
+
+func (c *menuCascade) track(x, y float64) {
+	level, row := c.hit(x, y)
+	switch {
+	case level < 0:
+		c.pendingLevel = -1
+	case level == len(c.path):
+		r := c.rowsAt(level)[row]
+		c.hover = -1
+		if r.selectable() {
+			c.hover = row
+		}
+		c.schedule(level, row, r.submenu && r.selectable())
+	case row == c.path[level]:
+		// Back on the row whose submenu is open: keep it, close the ones below.
+		c.path = c.path[:level+1]
+		c.hover = -1
+		c.pendingLevel = -1
+	default:
+		c.schedule(level, row, true)
+	}
+}
+
+// schedule starts the delay for a row, unless it is already running for that row.
+func (c *menuCascade) schedule(level, row int, want bool) {
+	if !want {
+		c.pendingLevel = -1
+		return
+	}
+	if c.pendingLevel == level && c.pendingRow == row {
+		return
+	}
+	c.pendingLevel, c.pendingRow, c.pendingTicks = level, row, 0
+}
+
+// openPending makes the row the delay ran for the highlighted row of its level, opening
+// its submenu if it has one.
+func (c *menuCascade) openPending() {
+	level, row := c.pendingLevel, c.pendingRow
+	c.pendingLevel = -1
+	c.path = c.path[:level]
+	r := c.rowsAt(level)[row]
+	c.hover = -1
+	switch {
+	case r.submenu && r.selectable():
+		c.open(level, row)
+	case r.selectable():
+		c.hover = row
+	}
+}
+
+// leave clears the highlight when the pointer leaves the menu; open submenus stay open.
+func (c *menuCascade) leave() {
+	c.hover = -1
+	c.pendingLevel = -1
+}
+
+// handleKey moves through the levels with the arrow keys, Home and End. Right opens the
+// submenu of the highlighted row and Left closes the deepest submenu. It reports whether
+// the key was used; Left on level 0 and Right on a row without a submenu are not.
+func (c *menuCascade) handleKey(k ebiten.Key) bool {
+	c.pendingLevel = -1
+	switch k {
+	case ebiten.KeyArrowDown:
+		c.step(1)
+	case ebiten.KeyArrowUp:
+		c.step(-1)
+	case ebiten.KeyHome:
+		c.hover = -1
+		c.step(1)
+	case ebiten.KeyEnd:
+		c.hover = -1
+		c.step(-1)
+	case ebiten.KeyArrowRight:
+		return c.openHovered()
+	case ebiten.KeyArrowLeft:
+		return c.back()
+	default:
+		return false
+	}
+	return true
+}
+
+// typeahead highlights the next row of the deepest level that typing key selects (see
+// menuMatches). It returns the row, or -1 when none matches, and whether it is the only
+// match.
+func (c *menuCascade) typeahead(key rune) (int, bool) {
+	c.pendingLevel = -1
+	matches := menuMatches(c.rowsAt(len(c.path)), key)
+	if len(matches) == 0 {
+		return -1, false
+	}
+	c.hover = nextMatch(matches, c.hover)
+	return c.hover, len(matches) == 1
+}
+
+// back closes the deepest submenu, highlighting the row that opened it. It reports
+// whether a submenu was open.
+func (c *menuCascade) back() bool {
+	if len(c.path) == 0 {
+		return false
+	}
+	last := len(c.path) - 1
+	c.hover = c.path[last]
+	c.path = c.path[:last]
+	c.pendingLevel = -1
+	return true
+}
+
+// openHovered opens the submenu of the highlighted row and highlights its first row.
+// It reports whether the row has a submenu.
+func (c *menuCascade) openHovered() bool {
+	level := len(c.path)
+	if c.hover < 0 {
+		return false
+	}
+	r := c.rowsAt(level)[c.hover]
+	if !r.submenu || !r.selectable() {
+		return false
+	}
+	c.open(level, c.hover)
+	c.step(1)
+	return true
+}
+
+// step moves the highlight of the deepest level by dir selectable rows, wrapping around.
+// From no highlight, 1 highlights the first selectable row and -1 the last.
+func (c *menuCascade) step(dir int) {
+	var selectable []int
+	for i, r := range c.rowsAt(len(c.path)) {
+		if r.selectable() {
+			selectable = append(selectable, i)
+		}
+	}
+	if len(selectable) == 0 {
+		return
+	}
+	pos := slices.Index(selectable, c.hover)
+	switch {
+	case pos < 0 && dir > 0:
+		pos = 0
+	case pos < 0:
+		pos = len(selectable) - 1
+	default:
+		pos = (pos + dir + len(selectable)) % len(selectable)
+	}
+	c.hover = selectable[pos]
+}
+
+// selected returns the level and row keyboard activation applies to, or -1, -1.
+func (c *menuCascade) selected() (level, row int) {
+	if c.hover < 0 {
+		return -1, -1
+	}
+	return len(c.path), c.hover
+}
+
+const (
+	menuRowPaddingX      = 10.0 // space between the level edges and the row content
+	menuIconSize         = 16.0 // size of the icon and check mark column
+	menuIconGap          = 6.0  // space between the icon column and the label
+	menuShortcutGap      = 24.0 // least space between a label and its shortcut
+	menuSubArrowSpace    = 16.0 // room for the arrow of rows with a submenu
+	menuSubmenuArrowSize = 4.0  // width of the arrow marking rows with a submenu
+)
+
+// hasIconColumn reports whether any row of a level has an icon or a check mark, so the
+// labels of the level leave room for them.
+func hasIconColumn(rows []menuRow) bool {
+	for _, r := range rows {
+		if r.icon != nil || r.mark != menuMarkNone {
+			return true
+		}
+	}
+	return false
+}
+
+// menuLevelWidth returns the width that fits every row of a level, at least minW. measure
+// returns the width of a text in the face the menu is drawn with.
+func menuLevelWidth(rows []menuRow, measure func(s string) float64, minW float64) float64 {
+	var label, shortcut float64
+	submenu := false
+	for _, r := range rows {
+		if r.separator {
+			continue
+		}
+		label = max(label, measure(r.label))
+		if r.shortcut != "" {
+			shortcut = max(shortcut, measure(r.shortcut))
+		}
+		submenu = submenu || r.submenu
+	}
+	w := label + menuRowPaddingX*2
+	if hasIconColumn(rows) {
+		w += menuIconSize + menuIconGap
+	}
+	if shortcut > 0 {
+		w += menuShortcutGap + shortcut
+	}
+	if submenu {
+		w += menuSubArrowSpace
+	}
+	return max(w, minW)
+}
+
+// menuColors are the colors a menu level is drawn with.
+type menuColors struct {
+	fill, stroke, hover, text, disabled, separator colors.Color
+}
+
+// drawLevel draws an open level: its background, then for each row the highlight, the
+// icon or check mark, the label, the shortcut at the right and the arrow of submenus.
+func (c *menuCascade) drawLevel(dst *ebiten.Image, face text.Face, level int, clr menuColors) {
+	rows := c.rowsAt(level)
+	if len(rows) == 0 {
+		return
+	}
+	b := c.bounds(level)
+	rendering.FillRect(dst, b.X, b.Y, b.W, b.H, clr.fill)
+	rendering.DrawStrokeRect(dst, b.X, b.Y, b.W, b.H, 1.0, clr.stroke)
+
+	iconColumn := hasIconColumn(rows)
+	active := c.active(level)
+	for i, r := range c.rowRects(level) {
+		row := rows[i]
+		if row.separator {
+			rendering.FillRect(dst, r.X+6, r.Y+r.H/2, r.W-12, 1, clr.separator)
+			continue
+		}
+		if i == active && row.selectable() {
+			rendering.FillRect(dst, r.X, r.Y, r.W, r.H, clr.hover)
+		}
+		textColor := clr.text
+		if row.disabled {
+			textColor = clr.disabled
+		}
+
+		x := r.X + menuRowPaddingX
+		if iconColumn {
+			icon := layout.Rect{X: x, Y: r.Y + (r.H-menuIconSize)/2, W: menuIconSize, H: menuIconSize}
+			drawMenuIcon(dst, row, icon, textColor)
+			x += menuIconSize + menuIconGap
+		}
+		textY := textTopY(row.label, face, r.Y, r.H)
+		rendering.DrawText(dst, row.label, face, int(x), textY, textColor)
+		drawMnemonic(dst, row.label, row.mnemonic, face, x, float64(textY), textColor)
+
+		right := r.X + r.W - menuRowPaddingX
+		if row.submenu {
+			drawSubmenuArrow(dst, right, r.Y+r.H/2, textColor)
+		} else if row.shortcut != "" {
+			sx := right - text.Advance(row.shortcut, face)
+			rendering.DrawText(dst, row.shortcut, face, int(sx), textTopY(row.shortcut, face, r.Y, r.H), textColor)
+		}
+	}
+}
+
+// drawMnemonic underlines the mnemonic character at byte index i of a label drawn at x, y.
+func drawMnemonic(dst *ebiten.Image, label string, i int, face text.Face, x, y float64, c colors.Color) {
+	if i < 0 || i >= len(label) {
+		return
+	}
+	_, size := utf8.DecodeRuneInString(label[i:])
+	x += text.Advance(label[:i], face)
+	y += face.Metrics().HAscent + 2
+	rendering.FillRect(dst, x, y, text.Advance(label[i:i+size], face), 1, c)
+}
+
+// drawMenuIcon draws the icon of a row, or its check mark when it has none. A checked row
+// with an icon gets a frame around the icon.
+func drawMenuIcon(dst *ebiten.Image, row menuRow, r layout.Rect, c colors.Color) {
+	if row.icon != nil {
+		rendering.DrawImageFit(dst, row.icon, r.X+2, r.Y+2, r.W-4, r.H-4)
+		if row.checked {
+			rendering.DrawStrokeRect(dst, r.X, r.Y, r.W, r.H, 1, c)
+		}
+		return
+	}
+	if !row.checked {
+		return
+	}
+	switch row.mark {
+	case menuMarkCheck:
+		midX, midY := r.X+r.W*0.4, r.Y+r.H-4
+		rendering.StrokeLine(dst, r.X+3, r.Y+r.H*0.55, midX, midY, 2, c)
+		rendering.StrokeLine(dst, midX, midY, r.X+r.W-3, r.Y+4, 2, c)
+	case menuMarkRadio:
+		rendering.DrawFilledCircle(dst, r.X+r.W/2, r.Y+r.H/2, 4, c)
+	}
+}
+
+// drawSubmenuArrow draws a small right-pointing arrow whose tip is at x, centered on cy.
+func drawSubmenuArrow(dst *ebiten.Image, x, cy float64, c colors.Color) {
+	for i := 0.0; i < menuSubmenuArrowSize; i++ {
+		h := (menuSubmenuArrowSize - i) * 2
+		rendering.FillRect(dst, x-menuSubmenuArrowSize+i, cy-h/2, 1, h, c)
+	}
+}
@@ -11,22 +11,33 @@ import (
 
 // Checkbox is a toggleable control with a label.
 type Checkbox struct {
-	c         *layout.Container
+	Element
 	Label     string
+	Font      *Font          // nil uses the UI default font
+	Theme     *CheckboxTheme // nil uses the UI theme
 	Checked   bool
 	OnChanged func(bool)
 }
 
 // NewCheckbox creates a standalone checkbox. Add it with panel.AddCheckbox(cb), then set OnChanged.
 func NewCheckbox(width, height layout.Size, label string) *Checkbox {
-	return &Checkbox{c: layout.NewContainer(width, height), Label: label}
+	cb := &Checkbox{Label: label}
+	cb.Init(layout.NewContainer(width, height))
+	cb.SetFocusable(true)
+	cb.c.Measure = cb.measure
+	return cb
 }
 
-// Bounds returns the computed layout rect after Layout.
-func (cb *Checkbox) Bounds() layout.Rect { return cb.c.Bounds }
+const (
+	checkboxBoxSize  = 16.0
+	checkboxLabelGap = 8.0
+)
 
-// Container returns the layout node for this checkbox (internal use).
-func (cb *Checkbox) Container() *layout.Container { return cb.c }
+// measure reports the box plus label size for Fit sizing.
+func (cb *Checkbox) measure(float64) (float64, float64) {
+	tw, th := measureText(cb.ui, cb.Font, cb.Label)
+	return checkboxBoxSize + checkboxLabelGap + tw, max(checkboxBoxSize, th)
+}
 
 // CheckboxTheme controls checkbox drawing colors.
 type CheckboxTheme struct {
@@ -48,9 +59,14 @@ func DefaultCheckboxTheme() CheckboxTheme {
 	}
 }
 
-func (cb *Checkbox) Draw(dst *ebiten.Image, face text.GoTextFace, theme CheckboxTheme, hovered bool) {
+// Draw draws the check box and its label.
+func (cb *Checkbox) Draw(ctx *DrawContext) {
+	cb.draw(ctx.Dst, ctx.FaceFor(cb.Font), themeOr(cb.Theme, ctx.Theme.Checkbox), false)
+}
+
+func (cb *Checkbox) draw(dst *ebiten.Image, face text.Face, theme CheckboxTheme, hovered bool) {
 	bound := cb.Bounds()
-	boxSize := 16.0
+	boxSize := checkboxBoxSize
 	boxY := bound.Y + (bound.H-boxSize)/2
 
 	rendering.FillRect(dst, bound.X, boxY, boxSize, boxSize, theme.BoxFill)
@@ -65,7 +81,7 @@ func (cb *Checkbox) Draw(dst *ebiten.Image, face text.GoTextFace, theme Checkbox
 		rendering.FillRect(dst, bound.X, boxY, boxSize, boxSize, theme.HoverOverlay)
 	}
 
-	labelX := int(bound.X + boxSize + 8)
+	labelX := int(bound.X + boxSize + checkboxLabelGap)
 	labelY := textTopY(cb.Label, face, bound.Y, bound.H)
 	rendering.DrawText(dst, cb.Label, face, labelX, labelY, theme.Text)
 }
@@ -77,3 +93,14 @@ func (cb *Checkbox) Toggle() {
 		cb.OnChanged(cb.Checked)
 	}
 }
+
+// HandleEvent toggles the checkbox when it is pressed, or with Space or Enter while focused.
+func (cb *Checkbox) HandleEvent(e *Event) {
+	switch {
+	case e.isPrimaryPress():
+		cb.Toggle()
+	case isActivation(e):
+		cb.Toggle()
+		e.StopPropagation()
+	}
+}
@@ -11,20 +11,21 @@ import (
 // Root is the root element. Use ui.Root() to get it, then root.CreatePanel(...) or root.AddPanel(panel) to build the tree.
 // Scale is the content scale (1 = 1:1). Change it to scale the whole UI (e.g. 2 = 2x bigger).
 type Root struct {
-	ui    *UI
-	c     *layout.Container
+	Element
 	Scale float64 // default 1
 }
 
-// Container returns the underlying layout container (for layout.Layout). Internal use.
-func (r *Root) Container() *layout.Container { return r.c }
-
 // SetAlignment sets how direct children are positioned inside the root.
 func (r *Root) SetAlignment(horizontal, vertical layout.Alignment) {
 	r.c.HorizontalAlign = horizontal
 	r.c.VerticalAlign = vertical
 }
 
+// SetDirection sets whether direct children of the root are stacked vertically or horizontally.
+func (r *Root) SetDirection(d layout.Direction) {
+	r.c.Direction = d
+}
+
 // CreatePanel creates a new panel and adds it as a direct child of the root. Returns the panel.
 func (r *Root) CreatePanel(width, height layout.Size) *Panel {
 	p := NewPanel(width, height)
@@ -34,9 +35,19 @@ func (r *Root) CreatePanel(width, height layout.Size) *Panel {
 
 // AddPanel adds an existing panel (e.g. from NewPanel) as a direct child of the root. Reusable panels.
 func (r *Root) AddPanel(p *Panel) {
-	p.ui = r.ui
-	r.c.Children = append(r.c.Children, p.c)
-	r.ui.panels = append(r.ui.panels, p)
+	r.AddChild(p)
+}
+
+// CreateScrollPanel creates a new scroll panel and adds it as a direct child of the root.
+func (r *Root) CreateScrollPanel(width, height layout.Size) *ScrollPanel {
+	sp := NewScrollPanel(width, height)
+	r.AddScrollPanel(sp)
+	return sp
+}
+
+// AddScrollPanel adds an existing scroll panel as a direct child of the root.
+func (r *Root) AddScrollPanel(sp *ScrollPanel) {
+	r.AddChild(sp)
 }
 
 // CreateMenuBar creates a new menu bar and adds it as a direct child of the root.
@@ -48,34 +59,58 @@ func (r *Root) CreateMenuBar(height layout.Size, widthMode MenuBarWidthMode) *Me
 
 // AddMenuBar adds an existing menu bar as a direct child of the root.
 func (r *Root) AddMenuBar(m *MenuBar) {
-	m.ui = r.ui
-	r.c.Children = append(r.c.Children, m.c)
-	r.ui.menus = append(r.ui.menus, m)
+	r.AddChild(m)
 }
 
 // Panel is a container that draws a background and can contain more panels or buttons.
 // Background is optional; if nil the renderer uses its default.
 // Create with NewPanel for reuse, or use CreatePanel to create and add in one step.
 type Panel struct {
-	ui         *UI
-	c          *layout.Container
+	Element
 	Background *colors.Color
+	Theme      *PanelTheme // nil uses the UI theme
 }
 
 // NewPanel creates a standalone panel (not in the tree). Add it with root.AddPanel(panel) or parent.AddPanel(panel).
 func NewPanel(width, height layout.Size) *Panel {
-	return &Panel{c: layout.NewContainer(width, height)}
+	p := &Panel{}
+	p.Init(layout.NewContainer(width, height))
+	return p
 }
 
-// Container returns the layout node for this panel (internal use).
-func (p *Panel) Container() *layout.Container { return p.c }
-
 // SetAlignment sets how direct children are positioned inside this panel.
 func (p *Panel) SetAlignment(horizontal, vertical layout.Alignment) {
 	p.c.HorizontalAlign = horizontal
 	p.c.VerticalAlign = vertical
 }
 
+// SetDirection sets whether direct children are stacked vertically or horizontally.
+func (p *Panel) SetDirection(d layout.Direction) {
+	p.c.Direction = d
+}
+
+// SetPadding sets the inner spacing between the panel edges and its children.
+func (p *Panel) SetPadding(padding layout.Padding) {
+	p.c.Padding = padding
+}
+
+// SetGap sets the space between consecutive children along the stacking direction.
+func (p *Panel) SetGap(gap float64) {
+	p.c.ChildGap = gap
+}
+
+// SetWrap makes children that overflow the stacking direction continue on a new line,
+// with lineGap between lines. A Fit-height row panel grows to fit every line.
+func (p *Panel) SetWrap(wrap bool, lineGap float64) {
+	p.c.Wrap = wrap
+	p.c.LineGap = lineGap
+}
+
+// SetOverflow sets whether children that extend past the panel are visible, clipped or scrollable.
+func (p *Panel) SetOverflow(o layout.Overflow) {
+	p.c.Overflow = o
+}
+
 // SetBackground sets panel background color.
 func (p *Panel) SetBackground(c colors.Color) {
 	p.Background = &c
@@ -92,9 +127,6 @@ func (p *Panel) SetBackgroundHex(hex string) bool {
 	return true
 }
 
-// Bounds returns the computed layout rect after Layout.
-func (p *Panel) Bounds() layout.Rect { return p.c.Bounds }
-
 // CreatePanel creates a new child panel and adds it. Returns the panel.
 func (p *Panel) CreatePanel(width, height layout.Size) *Panel {
 	child := NewPanel(width, height)
@@ -104,9 +136,31 @@ func (p *Panel) CreatePanel(width, height layout.Size) *Panel {
 
 // AddPanel adds an existing panel (e.g. from NewPanel) as a child. Reusable panels.
 func (p *Panel) AddPanel(child *Panel) {
-	child.ui = p.ui
-	p.c.Children = append(p.c.Children, child.c)
-	p.ui.panels = append(p.ui.panels, child)
+	p.AddChild(child)
+}
+
+// CreateScrollPanel creates a new child scroll panel and adds it. Returns the scroll panel.
+func (p *Panel) CreateScrollPanel(width, height layout.Size) *ScrollPanel {
+	sp := NewScrollPanel(width, height)
+	p.AddScrollPanel(sp)
+	return sp
+}
+
+// AddScrollPanel adds an existing scroll panel as a child.
+func (p *Panel) AddScrollPanel(sp *ScrollPanel) {
+	p.AddChild(sp)
+}
+
+// CreateGrid creates a new child grid with the given column and row tracks and adds it.
+func (p *Panel) CreateGrid(width, height layout.Size, cols, rows []layout.Size) *Grid {
+	g := NewGrid(width, height, cols, rows)
+	p.AddGrid(g)
+	return g
+}
+
+// AddGrid adds an existing grid as a child.
+func (p *Panel) AddGrid(g *Grid) {
+	p.AddChild(g)
 }
 
 // CreateButton creates a new button and adds it to this panel. Returns the button.
@@ -118,8 +172,7 @@ func (p *Panel) CreateButton(width, height layout.Size, label string) *Button {
 
 // AddButton adds an existing button (e.g. from NewButton) to this panel. Reuse same style, set OnClick per instance.
 func (p *Panel) AddButton(b *Button) {
-	p.c.Children = append(p.c.Children, b.c)
-	p.ui.buttons = append(p.ui.buttons, b)
+	p.AddChild(b)
 }
 
 // CreateMenuBar creates a new menu bar and adds it to this panel.
@@ -131,9 +184,7 @@ func (p *Panel) CreateMenuBar(height layout.Size, widthMode MenuBarWidthMode) *M
 
 // AddMenuBar adds an existing menu bar to this panel.
 func (p *Panel) AddMenuBar(m *MenuBar) {
-	m.ui = p.ui
-	p.c.Children = append(p.c.Children, m.c)
-	p.ui.menus = append(p.ui.menus, m)
+	p.AddChild(m)
 }
 
 // CreateCheckbox creates a new checkbox and adds it to this panel. Returns the checkbox.
@@ -145,8 +196,7 @@ func (p *Panel) CreateCheckbox(width, height layout.Size, label string) *Checkbo
 
 // AddCheckbox adds an existing checkbox to this panel.
 func (p *Panel) AddCheckbox(cb *Checkbox) {
-	p.c.Children = append(p.c.Children, cb.Container())
-	p.ui.checkboxes = append(p.ui.checkboxes, cb)
+	p.AddChild(cb)
 }
 
 // CreateRadioGroup creates a new radio group and adds it to this panel. Returns the radio group.
@@ -158,8 +208,7 @@ func (p *Panel) CreateRadioGroup(width, height layout.Size, options []RadioOptio
 
 // AddRadioGroup adds an existing radio group to this panel.
 func (p *Panel) AddRadioGroup(rg *RadioGroup) {
-	p.c.Children = append(p.c.Children, rg.Container())
-	p.ui.radioGroups = append(p.ui.radioGroups, rg)
+	p.AddChild(rg)
 }
 
 // CreateSlider creates a new slider and adds it to this panel. Returns the slider.
@@ -171,8 +220,7 @@ func (p *Panel) CreateSlider(width, height layout.Size, label string, min, max,
 
 // AddSlider adds an existing slider to this panel.
 func (p *Panel) AddSlider(s *Slider) {
-	p.c.Children = append(p.c.Children, s.Container())
-	p.ui.sliders = append(p.ui.sliders, s)
+	p.AddChild(s)
 }
 
 // CreateDropdown creates a new dropdown and adds it to this panel. Returns the dropdown.
@@ -184,13 +232,50 @@ func (p *Panel) CreateDropdown(width, height layout.Size, label string, options
 
 // AddDropdown adds an existing dropdown to this panel.
 func (p *Panel) AddDropdown(dd *Dropdown) {
-	p.c.Children = append(p.c.Children, dd.Container())
-	p.ui.dropdowns = append(p.ui.dropdowns, dd)
+	p.AddChild(dd)
+}
+
+// CreateLabel creates a new label and adds it to this panel. Returns the label.
+func (p *Panel) CreateLabel(width, height layout.Size, text string) *Label {
+	l := NewLabel(width, height, text)
+	p.AddLabel(l)
+	return l
+}
+
+// AddLabel adds an existing label to this panel.
+func (p *Panel) AddLabel(l *Label) {
+	p.AddChild(l)
 }
 
-// AddContextMenu adds a context menu to this panel (not part of layout tree).
+// CreateTextInput creates a new text input and adds it to this panel. Returns the text input.
+func (p *Panel) CreateTextInput(width, height layout.Size, placeholder string) *TextInput {
+	ti := NewTextInput(width, height, placeholder)
+	p.AddTextInput(ti)
+	return ti
+}
+
+// AddTextInput adds an existing text input to this panel.
+func (p *Panel) AddTextInput(ti *TextInput) {
+	p.AddChild(ti)
+}
+
+// CreateTextArea creates a new text area and adds it to this panel. Returns the text area.
+func (p *Panel) CreateTextArea(width, height layout.Size) *TextArea {
+	ta := NewTextArea(width, height)
+	p.AddTextArea(ta)
+	return ta
+}
+
+// AddTextArea adds an existing text area to this panel.
+func (p *Panel) AddTextArea(ta *TextArea) {
+	p.AddChild(ta)
+}
+
+// AddContextMenu adds a context menu to this panel, to be opened with cm.Open. It floats
+// over the viewport and does not take part in the panel's layout. To open it on
+// right-click, use SetContextMenu instead.
 func (p *Panel) AddContextMenu(cm *ContextMenu) {
-	p.ui.contextMenus = append(p.ui.contextMenus, cm)
+	p.AddChild(cm)
 }
 
 // PanelTheme controls panel drawing colors.
@@ -207,7 +292,12 @@ func DefaultPanelTheme() PanelTheme {
 	}
 }
 
-func (p *Panel) Draw(dst *ebiten.Image, theme PanelTheme) {
+// Draw draws the panel background and border.
+func (p *Panel) Draw(ctx *DrawContext) {
+	p.draw(ctx.Dst, themeOr(p.Theme, ctx.Theme.Panel))
+}
+
+func (p *Panel) draw(dst *ebiten.Image, theme PanelTheme) {
 	b := p.Bounds()
 	fill := theme.DefaultFill
 	if p.Background != nil {
@@ -3,7 +3,6 @@ package components
 import (
 	"goak/internal/goak/colors"
 	"goak/internal/goak/layout"
-	"goak/internal/goak/rendering"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
@@ -15,54 +14,104 @@ type ContextMenuItemKind int
 const (
 	ContextMenuItemAction ContextMenuItemKind = iota
 	ContextMenuItemSeparator
+	ContextMenuItemCheck // toggles Checked when run
+	ContextMenuItemRadio // checked when run, unchecking the other items of its Group
 )
 
-// ContextMenuItem is a context menu entry.
+// ContextMenuItem is a context menu entry. An action with SubItems opens a nested
+// submenu beside it instead of running OnClick.
 type ContextMenuItem struct {
 	Kind     ContextMenuItemKind
 	Label    string
 	OnClick  func()
 	Disabled bool
+	SubItems []ContextMenuItem
+
+	Checked  bool               // check mark of check and radio items
+	Group    string             // radio group, among the items of the same level
+	OnCheck  func(checked bool) // called with the new Checked state before OnClick
+	Icon     *ebiten.Image      // drawn before the label; nil for none
+	Shortcut string             // accelerator drawn at the right; pressing it runs the item
 }
 
-// ContextMenu is a right-click popup menu.
+// ContextMenu is a right-click popup menu. It floats over the viewport at the position it
+// was opened at, moved inside the viewport when it would overflow; nested submenus open
+// beside their item. Attach it to an element with SetContextMenu. While open it takes the
+// keyboard: Up and Down move the highlight, Right and Left open and close submenus, Enter
+// or Space run the highlighted item, a letter or digit selects items by mnemonic ("&Copy")
+// or first letter as in MenuBar, and Escape closes the menu. Item shortcuts are bound as
+// accelerators scoped to the parent of the menu (see Accelerators): they run their item,
+// with the menu closed, while focus is inside the parent.
 type ContextMenu struct {
-	Items        []ContextMenuItem
-	isOpen       bool
-	x            float64
-	y            float64
-	hoveredIndex int
-	itemHeight   float64
-	separatorH   float64
-	minWidth     float64
+	Element
+	Items      []ContextMenuItem
+	Font       *Font             // nil uses the UI default font
+	Theme      *ContextMenuTheme // nil uses the UI theme
+	isOpen     bool
+	sub        *menuCascade // open levels of the menu
+	itemHeight float64
+	separatorH float64
+	minWidth   float64
+
+	shortcuts    menuShortcuts // item shortcuts bound in the UI accelerators
+	shortcutPath []int         // reused by walkShortcuts
 }
 
 // NewContextMenu creates a context menu with the given items.
 func NewContextMenu(items []ContextMenuItem) *ContextMenu {
-	return &ContextMenu{
-		Items:        items,
-		hoveredIndex: -1,
-		itemHeight:   24.0,
-		separatorH:   8.0,
-		minWidth:     150.0,
+	cm := &ContextMenu{
+		Items:      items,
+		itemHeight: 24.0,
+		separatorH: 8.0,
+		minWidth:   150.0,
+	}
+	cm.Init(layout.NewContainer(layout.FitSize(), layout.FitSize()))
+	cm.c.Floating = &layout.Floating{
+		AttachTo:        layout.AttachToViewport,
+		Element:         layout.AttachTopLeft,
+		Target:          layout.AttachTopLeft,
+		ClampToViewport: true,
 	}
+	cm.c.Measure = cm.measure
+	cm.sub = newMenuCascade(cm.c, cm.rows, cm.rowHeight, cm.levelWidth)
+	return cm
 }
 
+// SetContextMenu attaches cm to the element: right-clicking the element or a descendant
+// without a menu of its own opens cm at the cursor, and so do the Menu key and Shift+F10
+// while the element has focus. cm becomes a child of the element unless it already has a
+// parent. nil detaches the menu.
+func (e *Element) SetContextMenu(cm *ContextMenu) {
+	e.contextMenu = cm
+	if cm != nil && cm.parent == nil {
+		e.AddChild(cm)
+	}
+}
+
+// ContextMenu returns the menu attached with SetContextMenu, or nil.
+func (e *Element) ContextMenu() *ContextMenu { return e.contextMenu }
+
 // IsOpen returns whether the context menu is currently visible.
 func (cm *ContextMenu) IsOpen() bool { return cm.isOpen }
 
 // Open displays the context menu at the given position.
 func (cm *ContextMenu) Open(x, y float64) {
 	cm.isOpen = true
-	cm.x = x
-	cm.y = y
-	cm.hoveredIndex = -1
+	cm.c.Floating.OffsetX = x
+	cm.c.Floating.OffsetY = y
+	cm.sub.reset()
+	if cm.ui != nil {
+		cm.ui.menu = cm
+	}
 }
 
 // Close hides the context menu.
 func (cm *ContextMenu) Close() {
 	cm.isOpen = false
-	cm.hoveredIndex = -1
+	cm.sub.reset()
+	if cm.ui != nil && cm.ui.menu == Widget(cm) {
+		cm.ui.menu = nil
+	}
 }
 
 // SetItemHeight sets the height of each menu item.
@@ -91,6 +140,84 @@ func (cm *ContextMenu) AddSeparator() *ContextMenu {
 	return cm
 }
 
+// AddEntry adds an item, e.g. a check item:
+// AddEntry(ContextMenuItem{Kind: ContextMenuItemCheck, Label: "Pin", Checked: true}).
+func (cm *ContextMenu) AddEntry(item ContextMenuItem) *ContextMenu {
+	cm.Items = append(cm.Items, item)
+	return cm
+}
+
+// AddSubMenu adds an item that opens a nested submenu with the given items.
+func (cm *ContextMenu) AddSubMenu(label string, items []ContextMenuItem) *ContextMenu {
+	cm.Items = append(cm.Items, ContextMenuItem{
+		Kind:     ContextMenuItemAction,
+		Label:    label,
+		SubItems: items,
+	})
+	return cm
+}
+
+// items returns the items of the level reached through path, or nil.
+func (cm *ContextMenu) items(path []int) []ContextMenuItem {
+	items := cm.Items
+	for _, i := range path {
+		if i < 0 || i >= len(items) {
+			return nil
+		}
+		items = items[i].SubItems
+	}
+	return items
+}
+
+// rows describes a menu level to the cascade.
+func (cm *ContextMenu) rows(path []int) []menuRow {
+	items := cm.items(path)
+	rows := make([]menuRow, len(items))
+	for i, item := range items {
+		label, mnemonic := parseMnemonic(item.Label)
+		rows[i] = menuRow{
+			separator: item.Kind == ContextMenuItemSeparator,
+			disabled:  item.Disabled,
+			submenu:   len(item.SubItems) > 0,
+			label:     label,
+			mnemonic:  mnemonic,
+			shortcut:  item.Shortcut,
+			icon:      item.Icon,
+			mark:      contextMenuItemMark(item.Kind),
+			checked:   item.Checked,
+		}
+	}
+	return rows
+}
+
+func (cm *ContextMenu) rowHeight(r menuRow) float64 {
+	if r.separator {
+		return cm.separatorH
+	}
+	return cm.itemHeight
+}
+
+func contextMenuItemMark(kind ContextMenuItemKind) menuMark {
+	switch kind {
+	case ContextMenuItemCheck:
+		return menuMarkCheck
+	case ContextMenuItemRadio:
+		return menuMarkRadio
+	}
+	return menuMarkNone
+}
+
+// levelWidth returns the width of the level reached through path: the widest row plus
+// padding, at least the minimum width.
+func (cm *ContextMenu) levelWidth(path []int) float64 {
+	face := cm.ui.faceFor(cm.Font)
+	if face == nil {
+		return cm.minWidth
+	}
+	measure := func(s string) float64 { return text.Advance(s, face) }
+	return menuLevelWidth(cm.rows(path), measure, cm.minWidth)
+}
+
 // ContextMenuTheme controls context menu drawing colors.
 type ContextMenuTheme struct {
 	Fill         colors.Color
@@ -113,119 +240,250 @@ func DefaultContextMenuTheme() ContextMenuTheme {
 	}
 }
 
-func (cm *ContextMenu) Draw(dst *ebiten.Image, face text.GoTextFace, theme ContextMenuTheme) {
+// Draw draws the menu and its open submenus when it is open.
+func (cm *ContextMenu) Draw(ctx *DrawContext) {
+	cm.draw(ctx.Dst, ctx.FaceFor(cm.Font), themeOr(cm.Theme, ctx.Theme.ContextMenu))
+}
+
+func (cm *ContextMenu) draw(dst *ebiten.Image, face text.Face, theme ContextMenuTheme) {
 	if !cm.isOpen {
 		return
 	}
 
-	bounds := cm.Bounds()
-
-	rendering.FillRect(dst, bounds.X, bounds.Y, bounds.W, bounds.H, theme.Fill)
-	rendering.DrawStrokeRect(dst, bounds.X, bounds.Y, bounds.W, bounds.H, 1.0, theme.Stroke)
-
-	currentY := cm.y
-	actionIndex := 0
-	for _, item := range cm.Items {
-		if item.Kind == ContextMenuItemSeparator {
-			sepY := currentY + cm.separatorH/2
-			rendering.DrawLine(dst, cm.x+6, sepY, bounds.W-12, 1, theme.Separator, true)
-			currentY += cm.separatorH
-		} else {
-			if actionIndex == cm.hoveredIndex && !item.Disabled {
-				rendering.FillRect(dst, cm.x+1, currentY+1, bounds.W-2, cm.itemHeight-2, theme.Hover)
-			}
-
-			textColor := theme.Text
-			if item.Disabled {
-				textColor = theme.DisabledText
-			}
-			textY := textTopY(item.Label, face, currentY, cm.itemHeight)
-			rendering.DrawText(dst, item.Label, face, int(cm.x+10), textY, textColor)
-
-			currentY += cm.itemHeight
-			actionIndex++
-		}
+	clr := menuColors{
+		fill:      theme.Fill,
+		stroke:    theme.Stroke,
+		hover:     theme.Hover,
+		text:      theme.Text,
+		disabled:  theme.DisabledText,
+		separator: theme.Separator,
+	}
+	for level := range cm.sub.depth() {
+		cm.sub.drawLevel(dst, face, level, clr)
 	}
 }
 
+// Bounds returns the rect of the first menu level after Layout, or an empty rect while
+// closed.
 func (cm *ContextMenu) Bounds() layout.Rect {
 	if !cm.isOpen {
 		return layout.Rect{}
 	}
+	return cm.c.Bounds
+}
 
-	width := cm.minWidth
-	height := 0.0
-	for _, item := range cm.Items {
-		if item.Kind == ContextMenuItemSeparator {
-			height += cm.separatorH
-		} else {
-			height += cm.itemHeight
-		}
+// measure reports the menu size while open, so the floating node is sized to the items.
+func (cm *ContextMenu) measure(float64) (float64, float64) {
+	if !cm.isOpen {
+		return 0, 0
+	}
+	return cm.sub.measure(0)
+}
+
+// syncLayout lines up open submenus and binds the item shortcuts, scoped to the parent of
+// the menu.
+func (cm *ContextMenu) syncLayout() {
+	cm.sub.sync()
+	ui, scope := cm.ui, Widget(nil)
+	if cm.parent == nil {
+		ui = nil
+	} else {
+		scope = cm.parent.self
 	}
+	cm.shortcuts.sync(ui, scope, cm.walkShortcuts, cm.runShortcut, cm.shortcutEnabled)
+}
 
-	return layout.Rect{X: cm.x, Y: cm.y, W: width, H: height}
+// HitTest reports whether the point is on the open menu or one of its submenus.
+func (cm *ContextMenu) HitTest(x, y float64) bool {
+	return cm.isOpen && cm.sub.contains(x, y)
 }
 
-// HitTest returns the action index at the given point, or -1.
+// HitTestItem returns the action index at the given point on the first menu level, or -1.
 // Skips separators and disabled items.
-func (cm *ContextMenu) HitTest(x, y float64) int {
+func (cm *ContextMenu) HitTestItem(x, y float64) int {
 	if !cm.isOpen {
 		return -1
 	}
-
-	bounds := cm.Bounds()
-	if !rendering.PointWithinBounds(x, y, bounds) {
+	level, row := cm.sub.hit(x, y)
+	if level != 0 || cm.Items[row].Kind == ContextMenuItemSeparator || cm.Items[row].Disabled {
 		return -1
 	}
+	return cm.actionIndex(row)
+}
+
+// SetHovered sets which action index of the first menu level is hovered (-1 for none),
+// closing open submenus.
+func (cm *ContextMenu) SetHovered(actionIndex int) {
+	cm.sub.reset()
+	cm.sub.hover = cm.itemIndex(actionIndex)
+}
+
+// Click runs the action at actionIndex of the first menu level, or opens its submenu.
+func (cm *ContextMenu) Click(actionIndex int) {
+	if index := cm.itemIndex(actionIndex); index >= 0 {
+		cm.activate(0, index)
+	}
+}
 
-	currentY := cm.y
-	actionIndex := 0
-	for _, item := range cm.Items {
+// itemIndex converts an action index (counting all items but separators) to an index in
+// Items, or -1.
+func (cm *ContextMenu) itemIndex(actionIndex int) int {
+	count := 0
+	for i, item := range cm.Items {
 		if item.Kind == ContextMenuItemSeparator {
-			currentY += cm.separatorH
-		} else {
-			if y >= currentY && y < currentY+cm.itemHeight {
-				if !item.Disabled {
-					return actionIndex
+			continue
+		}
+		if count == actionIndex {
+			return i
+		}
+		count++
+	}
+	return -1
+}
+
+// actionIndex converts an index in Items to an action index.
+func (cm *ContextMenu) actionIndex(itemIndex int) int {
+	count := 0
+	for _, item := range cm.Items[:itemIndex] {
+		if item.Kind != ContextMenuItemSeparator {
+			count++
+		}
+	}
+	return count
+}
+
+// activate runs an item and closes the menu, or opens the item's submenu. Separators and
+// disabled items do nothing.
+func (cm *ContextMenu) activate(level, row int) {
+	items := cm.items(cm.sub.path[:level])
+	item := items[row]
+	switch {
+	case item.Kind == ContextMenuItemSeparator || item.Disabled:
+	case len(item.SubItems) > 0:
+		cm.sub.open(level, row)
+	default:
+		runContextMenuItem(items, row)
+		cm.Close()
+	}
+}
+
+// runContextMenuItem runs items[i]: a check item toggles, a radio item is checked and the
+// other items of its group unchecked, then OnCheck and OnClick are called.
+func runContextMenuItem(items []ContextMenuItem, i int) {
+	item := &items[i]
+	switch item.Kind {
+	case ContextMenuItemCheck:
+		item.Checked = !item.Checked
+	case ContextMenuItemRadio:
+		for j := range items {
+			if items[j].Kind == ContextMenuItemRadio && items[j].Group == item.Group {
+				items[j].Checked = j == i
+			}
+		}
+	}
+	if item.OnCheck != nil && item.Kind != ContextMenuItemAction {
+		item.OnCheck(item.Checked)
+	}
+	if item.OnClick != nil {
+		item.OnClick()
+	}
+}
+
+// walkShortcuts visits the shortcuts of the items and their submenus.
+func (cm *ContextMenu) walkShortcuts(visit func(keys Shortcut, path []int)) {
+	path := cm.shortcutPath[:0]
+	var walkItems func(items []ContextMenuItem)
+	walkItems = func(items []ContextMenuItem) {
+		for i, item := range items {
+			path = append(path, i)
+			if len(item.SubItems) > 0 {
+				walkItems(item.SubItems)
+			} else if item.Shortcut != "" {
+				if keys, ok := cm.shortcuts.parse(item.Shortcut); ok {
+					visit(keys, path)
 				}
-				return -1
 			}
-			currentY += cm.itemHeight
-			actionIndex++
+			path = path[:len(path)-1]
 		}
 	}
+	walkItems(cm.Items)
+	cm.shortcutPath = path
+}
 
-	return -1
+// itemAt returns the item at path as the slice holding it and its index, or nil when the
+// path no longer exists or passes through a separator or disabled item.
+func (cm *ContextMenu) itemAt(path []int) ([]ContextMenuItem, int) {
+	items := cm.Items
+	for depth, i := range path {
+		if i >= len(items) || items[i].Kind == ContextMenuItemSeparator || items[i].Disabled {
+			return nil, 0
+		}
+		if depth == len(path)-1 {
+			return items, i
+		}
+		items = items[i].SubItems
+	}
+	return nil, 0
 }
 
-// SetHovered sets which action index is hovered (-1 for none).
-func (cm *ContextMenu) SetHovered(actionIndex int) {
-	cm.hoveredIndex = actionIndex
+// runShortcut closes the menu and runs the item at path.
+func (cm *ContextMenu) runShortcut(path []int) {
+	if items, i := cm.itemAt(path); items != nil {
+		cm.Close()
+		runContextMenuItem(items, i)
+	}
 }
 
-func (cm *ContextMenu) Click(actionIndex int) {
-	if actionIndex < 0 {
+// shortcutEnabled reports whether the item at path can run.
+func (cm *ContextMenu) shortcutEnabled(path []int) bool {
+	items, _ := cm.itemAt(path)
+	return items != nil
+}
+
+// HandleEvent highlights the item under the cursor, opens submenus and runs the pressed
+// item. Keys and wheel events go no further while the menu is open.
+func (cm *ContextMenu) HandleEvent(e *Event) {
+	if !cm.isOpen {
 		return
 	}
-
-	realIndex := 0
-	count := 0
-	for i, item := range cm.Items {
-		if item.Kind == ContextMenuItemAction {
-			if count == actionIndex {
-				realIndex = i
-				break
-			}
-			count++
+	switch e.Type {
+	case EventMouseMove:
+		cm.sub.pointerMove(e.X, e.Y)
+	case EventMouseLeave:
+		cm.sub.leave()
+	case EventMouseDown:
+		if level, row := cm.sub.hit(e.X, e.Y); level >= 0 && e.Button == ebiten.MouseButtonLeft {
+			cm.activate(level, row)
 		}
+		e.StopPropagation()
+	case EventWheel, EventKeyUp, EventTextInput:
+		e.StopPropagation()
+	case EventKeyDown:
+		cm.handleKey(e)
+		e.StopPropagation()
 	}
+}
 
-	if realIndex < len(cm.Items) {
-		item := cm.Items[realIndex]
-		if item.Kind == ContextMenuItemAction && !item.Disabled && item.OnClick != nil {
-			item.OnClick()
+func (cm *ContextMenu) handleKey(e *Event) {
+	switch {
+	case e.Key == ebiten.KeyEscape:
+		cm.Close()
+	case isActivation(e):
+		cm.activateSelected()
+	case cm.sub.handleKey(e.Key):
+	case isTypeahead(e):
+		if row, only := cm.sub.typeahead(keyRune(e.Key)); row >= 0 && only {
+			cm.activateSelected()
 		}
 	}
+}
 
-	cm.Close()
+// activateSelected opens the submenu of the highlighted item of the deepest level, or
+// runs the item.
+func (cm *ContextMenu) activateSelected() {
+	if !cm.sub.openHovered() {
+		if level, row := cm.sub.selected(); level >= 0 {
+			cm.activate(level, row)
+		}
+	}
 }
@@ -17,8 +17,11 @@ type DropdownOption struct {
 
 // Dropdown is a collapsible list of options.
 type Dropdown struct {
-	c             *layout.Container
+	Element
+	list          *layout.Container // floating node for the expanded list
 	Label         string
+	Font          *Font          // nil uses the UI default font
+	Theme         *DropdownTheme // nil uses the UI theme
 	Options       []DropdownOption
 	SelectedIndex int
 	OnChanged     func(int, string)
@@ -29,22 +32,30 @@ type Dropdown struct {
 
 // NewDropdown creates a standalone dropdown. Add it with panel.AddDropdown(dd).
 func NewDropdown(width, height layout.Size, label string, options []DropdownOption) *Dropdown {
-	return &Dropdown{
-		c:             layout.NewContainer(width, height),
+	dd := &Dropdown{
 		Label:         label,
 		Options:       options,
 		SelectedIndex: -1,
 		itemHeight:    24.0,
 		hoveredIndex:  -1,
 	}
+	dd.list = layout.NewContainer(layout.PercentOf(100), layout.FitSize())
+	dd.list.Floating = &layout.Floating{
+		Element:         layout.AttachTopLeft,
+		Target:          layout.AttachBottomLeft,
+		ClampToViewport: true,
+	}
+	dd.list.Measure = func(float64) (float64, float64) {
+		return 0, float64(len(dd.Options)) * dd.itemHeight
+	}
+	dd.Init(layout.NewContainer(width, height))
+	dd.SetFocusable(true)
+	list := &dropdownList{dd: dd}
+	list.Init(dd.list)
+	dd.AddChild(list)
+	return dd
 }
 
-// Bounds returns the computed layout rect after Layout.
-func (dd *Dropdown) Bounds() layout.Rect { return dd.c.Bounds }
-
-// Container returns the layout node for this dropdown (internal use).
-func (dd *Dropdown) Container() *layout.Container { return dd.c }
-
 // IsOpen returns whether the dropdown is currently expanded.
 func (dd *Dropdown) IsOpen() bool { return dd.isOpen }
 
@@ -93,7 +104,13 @@ func DefaultDropdownTheme() DropdownTheme {
 	}
 }
 
-func (dd *Dropdown) Draw(dst *ebiten.Image, face text.GoTextFace, theme DropdownTheme) {
+// Draw draws the collapsed dropdown box. The expanded list is a floating child widget, so
+// it is painted above other widgets.
+func (dd *Dropdown) Draw(ctx *DrawContext) {
+	dd.draw(ctx.Dst, ctx.FaceFor(dd.Font), themeOr(dd.Theme, ctx.Theme.Dropdown))
+}
+
+func (dd *Dropdown) draw(dst *ebiten.Image, face text.Face, theme DropdownTheme) {
 	bound := dd.Bounds()
 
 	rendering.FillRect(dst, bound.X, bound.Y, bound.W, bound.H, theme.Fill)
@@ -120,44 +137,42 @@ func (dd *Dropdown) Draw(dst *ebiten.Image, face text.GoTextFace, theme Dropdown
 		rendering.FillRect(dst, arrowX+1, arrowY+2, arrowSize-2, 1, theme.ArrowFill)
 		rendering.FillRect(dst, arrowX+2, arrowY+4, arrowSize-4, 1, theme.ArrowFill)
 	}
-
-	if dd.isOpen {
-		dd.drawList(dst, face, theme)
-	}
 }
 
-func (dd *Dropdown) drawList(dst *ebiten.Image, face text.GoTextFace, theme DropdownTheme) {
-	bound := dd.Bounds()
-	listY := bound.Y + bound.H
-	listHeight := float64(len(dd.Options)) * dd.itemHeight
+// drawList draws the expanded option list when the dropdown is open.
+func (dd *Dropdown) drawList(dst *ebiten.Image, face text.Face, theme DropdownTheme) {
+	if !dd.isOpen {
+		return
+	}
+	list := dd.ListBounds()
 
-	rendering.FillRect(dst, bound.X, listY, bound.W, listHeight, theme.Fill)
-	rendering.DrawStrokeRect(dst, bound.X, listY, bound.W, listHeight, 1.0, theme.Stroke)
+	rendering.FillRect(dst, list.X, list.Y, list.W, list.H, theme.Fill)
+	rendering.DrawStrokeRect(dst, list.X, list.Y, list.W, list.H, 1.0, theme.Stroke)
 
 	for i, opt := range dd.Options {
-		itemY := listY + float64(i)*dd.itemHeight
+		itemY := list.Y + float64(i)*dd.itemHeight
 
 		// Highlight selected or hovered
 		if i == dd.SelectedIndex {
-			rendering.FillRect(dst, bound.X+1, itemY+1, bound.W-2, dd.itemHeight-2, theme.Selected)
+			rendering.FillRect(dst, list.X+1, itemY+1, list.W-2, dd.itemHeight-2, theme.Selected)
 		} else if i == dd.hoveredIndex {
-			rendering.FillRect(dst, bound.X+1, itemY+1, bound.W-2, dd.itemHeight-2, theme.Hover)
+			rendering.FillRect(dst, list.X+1, itemY+1, list.W-2, dd.itemHeight-2, theme.Hover)
 		}
 
 		textY := textTopY(opt.Label, face, itemY, dd.itemHeight)
-		rendering.DrawText(dst, opt.Label, face, int(bound.X+8), textY, theme.Text)
+		rendering.DrawText(dst, opt.Label, face, int(list.X+8), textY, theme.Text)
 	}
 }
 
+// ListContainer returns the floating layout node of the expanded list (internal use).
+func (dd *Dropdown) ListContainer() *layout.Container { return dd.list }
+
 // ListBounds returns the bounds of the expanded list when open.
 func (dd *Dropdown) ListBounds() layout.Rect {
 	if !dd.isOpen {
 		return layout.Rect{}
 	}
-	bound := dd.Bounds()
-	listY := bound.Y + bound.H
-	listHeight := float64(len(dd.Options)) * dd.itemHeight
-	return layout.Rect{X: bound.X, Y: listY, W: bound.W, H: listHeight}
+	return dd.list.Bounds
 }
 
 // HitTestList returns the index of the option at the given point in the list, or -1.
@@ -193,3 +208,83 @@ func (dd *Dropdown) Select(index int) {
 	}
 	dd.Close()
 }
+
+// HandleEvent opens or closes the list when the box is pressed. While focused, Space,
+// Enter or Down open the list; when open, Up/Down move the highlight, Space/Enter select
+// the highlighted option and Escape closes the list.
+func (dd *Dropdown) HandleEvent(e *Event) {
+	switch e.Type {
+	case EventMouseDown:
+		if e.isPrimaryPress() {
+			dd.Toggle()
+		}
+	case EventKeyDown:
+		if e.Repeat && isActivateKey(e.Key) {
+			return
+		}
+		if dd.handleKey(e.Key) {
+			e.StopPropagation()
+		}
+	}
+}
+
+// handleKey applies keyboard navigation and reports whether the key was used.
+func (dd *Dropdown) handleKey(k ebiten.Key) bool {
+	if !dd.isOpen {
+		if isActivateKey(k) || k == ebiten.KeyArrowDown {
+			dd.Open()
+			dd.hoveredIndex = dd.SelectedIndex
+			return true
+		}
+		return false
+	}
+	switch {
+	case k == ebiten.KeyArrowDown:
+		dd.hoveredIndex = min(dd.hoveredIndex+1, len(dd.Options)-1)
+	case k == ebiten.KeyArrowUp:
+		dd.hoveredIndex = max(dd.hoveredIndex-1, 0)
+	case isActivateKey(k):
+		if dd.hoveredIndex >= 0 {
+			dd.Select(dd.hoveredIndex)
+		} else {
+			dd.Close()
+		}
+	case k == ebiten.KeyEscape:
+		dd.Close()
+	default:
+		return false
+	}
+	return true
+}
+
+// dropdownList is the floating widget showing the options of an open Dropdown.
+type dropdownList struct {
+	Element
+	dd *Dropdown
+}
+
+func (l *dropdownList) Draw(ctx *DrawContext) {
+	l.dd.drawList(ctx.Dst, ctx.FaceFor(l.dd.Font), themeOr(l.dd.Theme, ctx.Theme.Dropdown))
+}
+
+func (l *dropdownList) HitTest(x, y float64) bool {
+	return rendering.PointWithinBounds(x, y, l.dd.ListBounds())
+}
+
+// HandleEvent highlights the option under the cursor and selects the pressed one.
+func (l *dropdownList) HandleEvent(e *Event) {
+	if !l.dd.isOpen {
+		return
+	}
+	switch e.Type {
+	case EventMouseMove:
+		l.dd.SetHovered(l.dd.HitTestList(e.X, e.Y))
+	case EventMouseLeave:
+		l.dd.SetHovered(-1)
+	case EventMouseDown:
+		if index := l.dd.HitTestList(e.X, e.Y); index >= 0 && e.Button == ebiten.MouseButtonLeft {
+			l.dd.Select(index)
+		}
+		e.StopPropagation()
+	}
+}
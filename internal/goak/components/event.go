@@ -0,0 +1,173 @@
+package components
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// EventType identifies the kind of input event.
+type EventType int
+
+const (
+	// EventMouseMove is sent each frame to the widget under the cursor (or the pointer capture).
+	EventMouseMove EventType = iota
+	// EventMouseDown is sent when a mouse button is pressed; see Button.
+	EventMouseDown
+	// EventMouseUp is sent when a mouse button is released; see Button.
+	EventMouseUp
+	// EventMouseEnter is sent to a widget and each of its ancestors when the cursor moves onto
+	// it. It does not bubble.
+	EventMouseEnter
+	// EventMouseLeave is sent to a widget and each of its ancestors when the cursor moves off
+	// it. It does not bubble.
+	EventMouseLeave
+	// EventWheel is sent when the mouse wheel moves; see WheelX and WheelY.
+	EventWheel
+	// EventKeyDown is sent when a key is pressed, and repeatedly while it is held; see Key,
+	// Repeat and Mods.
+	EventKeyDown
+	// EventKeyUp is sent when a key is released; see Key and Mods.
+	EventKeyUp
+	// EventTextInput is sent with the characters typed this frame; see Text.
+	EventTextInput
+	// EventFocus is sent to a widget when it gains keyboard focus. It does not bubble.
+	EventFocus
+	// EventBlur is sent to a widget when it loses keyboard focus. It does not bubble.
+	EventBlur
+)
+
+// EventPhase tells where an event is in its trip through the tree.
+type EventPhase int
+
+const (
+	// PhaseCapture is the trip from the root down to the target's parent.
+	PhaseCapture EventPhase = iota
+	// PhaseTarget is delivery to the target itself.
+	PhaseTarget
+	// PhaseBubble is the trip from the target's parent back up to the root.
+	PhaseBubble
+)
+
+// Modifiers is a set of modifier keys held during an event.
+type Modifiers uint8
+
+const (
+	ModShift Modifiers = 1 << iota
+	ModCtrl
+	ModAlt
+	ModMeta
+)
+
+// Has reports whether all modifiers in m are held.
+func (mods Modifiers) Has(m Modifiers) bool {
+	return mods&m == m
+}
+
+// Event is an input event; pointer positions are in layout coordinates.
+// Pointer events go to the widget under the cursor (or the pointer capture) and keyboard
+// events to the focused widget (or the root). An event first travels down from the root
+// (capture phase), reaches the target, then bubbles back up until StopPropagation is called.
+type Event struct {
+	Type   EventType
+	X, Y   float64
+	Button ebiten.MouseButton
+	WheelX float64 // wheel offsets as reported by ebiten.Wheel (positive = left/up)
+	WheelY float64
+	Key    ebiten.Key
+	Repeat bool // EventKeyDown sent again because the key is held down
+	Mods   Modifiers
+	Text   string // typed characters for EventTextInput
+
+	Target        Widget // widget the event is addressed to
+	CurrentTarget Widget // widget whose handler is running
+	Phase         EventPhase
+
+	stopped bool
+}
+
+// StopPropagation keeps the event from reaching further widgets. Handlers of the current
+// widget still run.
+func (e *Event) StopPropagation() {
+	e.stopped = true
+}
+
+// Stopped reports whether StopPropagation was called.
+func (e *Event) Stopped() bool {
+	return e.stopped
+}
+
+// isPrimaryPress reports whether e is a left mouse button press.
+func (e *Event) isPrimaryPress() bool {
+	return e.Type == EventMouseDown && e.Button == ebiten.MouseButtonLeft
+}
+
+// listener is an event handler registered with On or OnCapture.
+type listener struct {
+	typ     EventType
+	capture bool
+	fn      func(e *Event)
+}
+
+// On registers fn for events of type t that reach this element as the target or while
+// bubbling up from a descendant, e.g. panel.On(EventMouseDown, fn) sees clicks on its children.
+func (e *Element) On(t EventType, fn func(ev *Event)) {
+	e.listeners = append(e.listeners, listener{typ: t, fn: fn})
+}
+
+// OnCapture registers fn for events of type t on their way down to a descendant target,
+// before the target sees them; call StopPropagation to intercept them.
+func (e *Element) OnCapture(t EventType, fn func(ev *Event)) {
+	e.listeners = append(e.listeners, listener{typ: t, capture: true, fn: fn})
+}
+
+// fire runs the element's listeners for ev registered for the given phase.
+func (e *Element) fire(ev *Event, capture bool) {
+	for _, l := range e.listeners {
+		if l.typ == ev.Type && l.capture == capture {
+			ev.CurrentTarget = e.self
+			l.fn(ev)
+		}
+	}
+}
+
+// deliver hands ev to a single element without propagation: the widget's HandleEvent,
+// then all of its listeners.
+func (e *Element) deliver(ev *Event) {
+	ev.Target = e.self
+	ev.CurrentTarget = e.self
+	ev.Phase = PhaseTarget
+	e.self.HandleEvent(ev)
+	e.fire(ev, true)
+	e.fire(ev, false)
+}
+
+// propagate sends ev to target through the capture, target and bubble phases.
+func propagate(target Widget, ev *Event) {
+	var path []*Element // target first, root last
+	for cur := target.element(); cur != nil; cur = cur.parent {
+		path = append(path, cur)
+	}
+	ev.Target = target
+
+	ev.Phase = PhaseCapture
+	for i := len(path) - 1; i > 0 && !ev.stopped; i-- {
+		path[i].fire(ev, true)
+	}
+	if ev.stopped {
+		return
+	}
+
+	ev.Phase = PhaseTarget
+	t := path[0]
+	t.fire(ev, true)
+	ev.CurrentTarget = target
+	target.HandleEvent(ev)
+	t.fire(ev, false)
+
+	ev.Phase = PhaseBubble
+	for _, cur := range path[1:] {
+		if ev.stopped {
+			return
+		}
+		ev.CurrentTarget = cur.self
+		cur.self.HandleEvent(ev)
+		cur.fire(ev, false)
+	}
+}
@@ -0,0 +1,188 @@
+package components
+
+import (
+	"cmp"
+	"goak/internal/goak/colors"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// FocusTheme controls the ring drawn around the focused widget.
+type FocusTheme struct {
+	Ring   colors.Color
+	Width  float64 // stroke width
+	Offset float64 // gap between the widget bounds and the ring
+}
+
+// DefaultFocusTheme returns the default focus ring theme.
+func DefaultFocusTheme() FocusTheme {
+	return FocusTheme{
+		Ring:   colors.HexOr("#4a9eff", colors.RGB(74, 158, 255)),
+		Width:  2,
+		Offset: 2,
+	}
+}
+
+// SetFocusable sets whether the element can receive keyboard focus, by clicking it or
+// with Tab. Built-in controls (buttons, checkboxes, ...) are focusable by default.
+func (e *Element) SetFocusable(focusable bool) {
+	e.focusable = focusable
+}
+
+// Focusable reports whether the element can receive keyboard focus.
+func (e *Element) Focusable() bool { return e.focusable }
+
+// SetTabIndex overrides the element's place in Tab order. Elements with a positive index
+// come first, in increasing order; 0 (the default) keeps tree order after them; a negative
+// index takes the element out of Tab order while it can still be focused by clicking.
+func (e *Element) SetTabIndex(index int) {
+	e.tabIndex = index
+}
+
+// TabIndex returns the index set with SetTabIndex.
+func (e *Element) TabIndex() int { return e.tabIndex }
+
+// Focused returns the widget with keyboard focus, or nil.
+func (u *UI) Focused() Widget {
+	return u.focus
+}
+
+// FocusVisible reports whether the focus ring should be drawn: focus moved by keyboard
+// shows it, focus set by clicking hides it.
+func (u *UI) FocusVisible() bool {
+	return u.focus != nil && u.focusVisible
+}
+
+// SetFocusTheme sets the focus ring theme of the current theme.
+func (u *UI) SetFocusTheme(theme FocusTheme) {
+	u.theme.Focus = theme
+}
+
+// FocusTheme returns the focus ring theme.
+func (u *UI) FocusTheme() FocusTheme {
+	return u.theme.Focus
+}
+
+// Focus gives keyboard focus to w and shows the focus ring; nil clears the focus.
+// Scroll panels containing w scroll to show it.
+func (u *UI) Focus(w Widget) {
+	u.setFocus(w, true)
+	if w == nil {
+		return
+	}
+	for cur := w.element().parent; cur != nil; cur = cur.parent {
+		if sp, ok := cur.self.(*ScrollPanel); ok {
+			sp.ScrollTo(w.Container().Bounds)
+		}
+	}
+}
+
+// Blur clears keyboard focus.
+func (u *UI) Blur() {
+	u.setFocus(nil, false)
+}
+
+// FocusNext moves focus to the next widget in Tab order, wrapping around.
+func (u *UI) FocusNext() {
+	u.moveFocus(1)
+}
+
+// FocusPrev moves focus to the previous widget in Tab order, wrapping around.
+func (u *UI) FocusPrev() {
+	u.moveFocus(-1)
+}
+
+func (u *UI) moveFocus(step int) {
+	order := u.tabOrder()
+	if len(order) == 0 {
+		return
+	}
+	i := slices.Index(order, u.focus)
+	switch {
+	case i < 0 && step > 0:
+		i = 0
+	case i < 0:
+		i = len(order) - 1
+	default:
+		i = (i + step + len(order)) % len(order)
+	}
+	u.Focus(order[i])
+}
+
+// tabOrder returns the focusable widgets reachable with Tab: positive tab indexes first,
+// then the rest in tree order. Widgets without a size (closed popups) are skipped.
+func (u *UI) tabOrder() []Widget {
+	var order []Widget
+	u.Walk(func(w Widget) bool {
+		e := w.element()
+		if e.focusable && e.tabIndex >= 0 && !e.c.Bounds.Empty() {
+			order = append(order, w)
+		}
+		return true
+	})
+	slices.SortStableFunc(order, func(a, b Widget) int {
+		return cmp.Compare(tabKey(a), tabKey(b))
+	})
+	return order
+}
+
+func tabKey(w Widget) int {
+	if i := w.element().tabIndex; i > 0 {
+		return i
+	}
+	return math.MaxInt
+}
+
+// setFocus moves focus to w, sending Blur to the old widget and Focus to the new one.
+func (u *UI) setFocus(w Widget, visible bool) {
+	u.focusVisible = visible
+	old := u.focus
+	if old == w {
+		return
+	}
+	u.focus = w
+	if old != nil {
+		old.element().deliver(&Event{Type: EventBlur})
+	}
+	if w != nil {
+		w.element().deliver(&Event{Type: EventFocus})
+	}
+}
+
+// focusFromPointer focuses the nearest focusable widget at or above target after a click,
+// or clears focus when there is none. Clicks in an open menu keep the focus.
+func (u *UI) focusFromPointer(target Widget) {
+	for cur := target.element(); cur != nil; cur = cur.parent {
+		if cur.focusable {
+			u.setFocus(cur.self, false)
+			return
+		}
+		if u.menu != nil && cur.self == u.menu {
+			return
+		}
+	}
+	u.setFocus(nil, false)
+}
+
+// closePopups closes every open popup.
+func (u *UI) closePopups() {
+	u.Walk(func(w Widget) bool {
+		if p, ok := w.(Popup); ok && p.IsOpen() {
+			p.Close()
+		}
+		return true
+	})
+}
+
+// isActivateKey reports whether k activates the focused control (Space or Enter).
+func isActivateKey(k ebiten.Key) bool {
+	return k == ebiten.KeySpace || k == ebiten.KeyEnter || k == ebiten.KeyNumpadEnter
+}
+
+// isActivation reports whether e is a first press of an activation key; held keys do not
+// activate again.
+func isActivation(e *Event) bool {
+	return e.Type == EventKeyDown && !e.Repeat && isActivateKey(e.Key)
+}
@@ -0,0 +1,245 @@
+package components
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// FontWeight is the thickness of a font on the usual 100 (thin) to 900 (black) scale.
+type FontWeight int
+
+const (
+	WeightThin     FontWeight = 100
+	WeightLight    FontWeight = 300
+	WeightRegular  FontWeight = 400
+	WeightMedium   FontWeight = 500
+	WeightSemiBold FontWeight = 600
+	WeightBold     FontWeight = 700
+	WeightBlack    FontWeight = 900
+)
+
+// FontStyle selects upright or italic faces.
+type FontStyle int
+
+const (
+	StyleNormal FontStyle = iota
+	StyleItalic
+)
+
+// Font describes the face a widget draws its text with. Zero fields (empty Family, zero
+// Size or Weight) take the registry default. Set a widget's Font field to use it, e.g.
+// title.Font = &components.Font{Size: 28, Weight: components.WeightBold}.
+type Font struct {
+	Family string
+	Size   float64 // in layout units
+	Weight FontWeight
+	Style  FontStyle
+}
+
+// over returns f with its zero fields taken from base.
+func (f Font) over(base Font) Font {
+	if f.Family == "" {
+		f.Family = base.Family
+	}
+	if f.Size == 0 {
+		f.Size = base.Size
+	}
+	if f.Weight == 0 {
+		f.Weight = base.Weight
+	}
+	return f
+}
+
+// fontVariant is one loaded font file of a family.
+type fontVariant struct {
+	weight FontWeight
+	style  FontStyle
+	source *text.GoTextFaceSource
+}
+
+// FontRegistry loads font files and hands out faces by family, size, weight and style.
+// A family is one or more loaded files (Regular, Bold, Italic, ...); a request picks the
+// file with the right style and the closest weight. Glyphs missing from a family are taken
+// from its fallback families, then from the default family, so one face can mix scripts.
+// The window creates a registry with a built-in font; get it with App.Fonts or UI.Fonts.
+type FontRegistry struct {
+	families  map[string][]fontVariant
+	fallbacks map[string][]string
+	named     map[string]Font
+	def       Font
+	faces     map[Font]text.Face // built faces, cleared when fonts change
+}
+
+// NewFontRegistry returns an empty registry. The first family loaded becomes the default.
+func NewFontRegistry() *FontRegistry {
+	return &FontRegistry{
+		families:  make(map[string][]fontVariant),
+		fallbacks: make(map[string][]string),
+		named:     make(map[string]Font),
+		def:       Font{Size: 16, Weight: WeightRegular},
+		faces:     make(map[Font]text.Face),
+	}
+}
+
+// LoadBytes adds a TTF or OTF font to family.
+func (r *FontRegistry) LoadBytes(family string, weight FontWeight, style FontStyle, data []byte) error {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("font %q: %w", family, err)
+	}
+	r.AddSource(family, weight, style, src)
+	return nil
+}
+
+// LoadFile reads a TTF or OTF file and adds it to family.
+func (r *FontRegistry) LoadFile(family string, weight FontWeight, style FontStyle, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("font %q: %w", family, err)
+	}
+	return r.LoadBytes(family, weight, style, data)
+}
+
+// AddSource adds an already parsed font to family. A zero weight means regular.
+func (r *FontRegistry) AddSource(family string, weight FontWeight, style FontStyle, src *text.GoTextFaceSource) {
+	if weight == 0 {
+		weight = WeightRegular
+	}
+	r.families[family] = append(r.families[family], fontVariant{weight: weight, style: style, source: src})
+	if r.def.Family == "" {
+		r.def.Family = family
+	}
+	clear(r.faces)
+}
+
+// HasFamily reports whether any font was loaded into family.
+func (r *FontRegistry) HasFamily(family string) bool {
+	return len(r.families[family]) > 0
+}
+
+// SetFallbacks sets the families searched, in order, for glyphs missing from family
+// (e.g. a CJK family behind a Latin one). The default family is always searched last.
+func (r *FontRegistry) SetFallbacks(family string, fallbacks ...string) {
+	r.fallbacks[family] = fallbacks
+	clear(r.faces)
+}
+
+// SetDefault sets the font used by widgets without a Font of their own, and to fill in
+// zero fields of other fonts. Zero fields of f keep the current default.
+func (r *FontRegistry) SetDefault(f Font) {
+	r.def = r.resolve(f)
+	clear(r.faces)
+}
+
+// Default returns the default font.
+func (r *FontRegistry) Default() Font {
+	return r.def
+}
+
+// Define names a font so it can be shared, e.g. Define("heading", Font{Size: 28}).
+func (r *FontRegistry) Define(name string, f Font) {
+	r.named[name] = f
+}
+
+// Named returns a copy of the font defined as name, ready to assign to a widget's Font
+// field, or nil (the default font) if name is not defined.
+func (r *FontRegistry) Named(name string) *Font {
+	f, ok := r.named[name]
+	if !ok {
+		return nil
+	}
+	return &f
+}
+
+// resolve fills the zero fields of f from the default font.
+func (r *FontRegistry) resolve(f Font) Font {
+	return f.over(r.def)
+}
+
+// Face returns the face for f, combined with its fallbacks, or nil if no font is loaded.
+// Faces are cached, so the same font always returns the same face.
+func (r *FontRegistry) Face(f Font) text.Face {
+	f = r.resolve(f)
+	if face, ok := r.faces[f]; ok {
+		return face
+	}
+	var faces []text.Face
+	seen := make(map[string]bool)
+	chain := append(append([]string{f.Family}, r.fallbacks[f.Family]...), r.def.Family)
+	for _, family := range chain {
+		if seen[family] {
+			continue
+		}
+		seen[family] = true
+		if v, ok := r.match(family, f.Weight, f.Style); ok {
+			faces = append(faces, &text.GoTextFace{Source: v.source, Size: f.Size})
+		}
+	}
+	var face text.Face
+	switch len(faces) {
+	case 0:
+		return nil
+	case 1:
+		face = faces[0]
+	default:
+		// All faces are horizontal, so NewMultiFace cannot fail.
+		face, _ = text.NewMultiFace(faces...)
+	}
+	r.faces[f] = face
+	return face
+}
+
+// match returns the variant of family closest to weight, preferring the requested style.
+func (r *FontRegistry) match(family string, weight FontWeight, style FontStyle) (fontVariant, bool) {
+	var best fontVariant
+	bestScore := -1
+	for _, v := range r.families[family] {
+		score := abs(int(v.weight - weight))
+		if v.style != style {
+			score += 1000
+		}
+		if bestScore < 0 || score < bestScore {
+			best, bestScore = v, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// SetFonts sets the font registry used to draw and measure text. The window sets its
+// own registry when the UI is attached, unless one was set before.
+func (u *UI) SetFonts(r *FontRegistry) {
+	u.fonts = r
+}
+
+// Fonts returns the font registry, or nil before one is set.
+func (u *UI) Fonts() *FontRegistry {
+	return u.fonts
+}
+
+// Face returns the face of the default font, or nil before fonts are set.
+func (u *UI) Face() text.Face {
+	return u.faceFor(nil)
+}
+
+// faceFor returns the face for f (nil = the default font), or nil before fonts are set.
+// Zero fields of f are taken from the theme font, then from the registry default.
+func (u *UI) faceFor(f *Font) text.Face {
+	if u == nil || u.fonts == nil {
+		return nil
+	}
+	font := u.theme.Font
+	if f != nil {
+		font = f.over(font)
+	}
+	return u.fonts.Face(font)
+}
@@ -0,0 +1,58 @@
+package components
+
+import "goak/internal/goak/layout"
+
+// Node is anything backed by a layout node, e.g. panels and widgets.
+type Node interface {
+	Container() *layout.Container
+}
+
+// Grid is a panel that places its children in column/row cells instead of stacking them.
+// Children added with the usual Panel methods (CreateButton, AddPanel, ...) fill the free
+// cells row by row; use Place or PlaceSpan to put one in a specific cell.
+type Grid struct {
+	*Panel
+}
+
+// NewGrid creates a standalone grid with the given column and row tracks
+// (e.g. layout.EqualTracks(3), or layout.StaticPx / layout.Grow / layout.FitSize per track).
+// Add it with parent.AddGrid(grid).
+func NewGrid(width, height layout.Size, cols, rows []layout.Size) *Grid {
+	p := NewPanel(width, height)
+	p.c.Grid = &layout.Grid{Columns: cols, Rows: rows}
+	return &Grid{Panel: p}
+}
+
+// SetGaps sets the space between columns and between rows.
+func (g *Grid) SetGaps(column, row float64) {
+	g.c.Grid.ColumnGap = column
+	g.c.Grid.RowGap = row
+}
+
+// Place puts a child of this grid in the cell at col, row.
+func (g *Grid) Place(child Node, col, row int) {
+	g.PlaceSpan(child, col, row, 1, 1)
+}
+
+// PlaceSpan puts a child of this grid at col, row covering colSpan columns and rowSpan rows.
+// The child is aligned in its cell like the grid's own alignment until SetCellAlignment.
+func (g *Grid) PlaceSpan(child Node, col, row, colSpan, rowSpan int) {
+	child.Container().Cell = &layout.GridCell{
+		Column:          col,
+		Row:             row,
+		ColumnSpan:      colSpan,
+		RowSpan:         rowSpan,
+		HorizontalAlign: g.c.HorizontalAlign,
+		VerticalAlign:   g.c.VerticalAlign,
+	}
+}
+
+// SetCellAlignment sets how a placed child is aligned inside its cell.
+func (g *Grid) SetCellAlignment(child Node, horizontal, vertical layout.Alignment) {
+	cell := child.Container().Cell
+	if cell == nil {
+		return
+	}
+	cell.HorizontalAlign = horizontal
+	cell.VerticalAlign = vertical
+}
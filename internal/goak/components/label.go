@@ -0,0 +1,188 @@
+package components
+
+import (
+	"goak/internal/goak/colors"
+	"goak/internal/goak/layout"
+	"goak/internal/goak/rendering"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Label shows static text. It reports its text size to the layout engine, so a FitSize
+// label shrink-wraps its text; with Wrap on, a label with a known width and FitSize height
+// grows to fit the wrapped lines. Text that does not fit (lines past MaxLines, or a line
+// wider than the label when Wrap is off) ends with an ellipsis.
+// Create with NewLabel; add with panel.AddLabel(label).
+type Label struct {
+	Element
+	Text            string
+	HorizontalAlign layout.Alignment // alignment of each line
+	VerticalAlign   layout.Alignment // alignment of the block of lines
+	Wrap            bool             // break lines between words to fit the width
+	MaxLines        int              // maximum number of lines shown; 0 means no limit
+	Color           *colors.Color    // text color; nil uses the theme
+	Font            *Font            // nil uses the UI default font
+	Theme           *LabelTheme      // nil uses the UI theme
+
+	cache [2]labelCache // lines measured without a width limit, and lines at the laid out width
+}
+
+// labelCache holds lines computed for one width, reused while their inputs are unchanged.
+type labelCache struct {
+	text     string
+	width    float64
+	wrap     bool
+	maxLines int
+	face     text.Face
+	lines    []string
+}
+
+const labelEllipsis = "…"
+
+// NewLabel creates a standalone label. Add it with panel.AddLabel(label).
+func NewLabel(width, height layout.Size, label string) *Label {
+	l := &Label{Text: label}
+	l.Init(layout.NewContainer(width, height))
+	l.c.Measure = l.measure
+	return l
+}
+
+// SetAlignment sets how lines are aligned horizontally and the text block vertically.
+func (l *Label) SetAlignment(horizontal, vertical layout.Alignment) {
+	l.HorizontalAlign = horizontal
+	l.VerticalAlign = vertical
+}
+
+// SetWrap sets whether long lines break between words to fit the label width.
+func (l *Label) SetWrap(wrap bool) {
+	l.Wrap = wrap
+}
+
+// SetMaxLines limits the number of lines shown; 0 removes the limit.
+func (l *Label) SetMaxLines(n int) {
+	l.MaxLines = n
+}
+
+// SetColor sets the text color.
+func (l *Label) SetColor(c colors.Color) {
+	l.Color = &c
+}
+
+// measure reports the size of the laid out text: unwrapped while widths are computed,
+// wrapped to availW once the width is known.
+func (l *Label) measure(availW float64) (float64, float64) {
+	face := l.ui.faceFor(l.Font)
+	if face == nil {
+		return 0, 0
+	}
+	lines := l.lines(face, availW)
+	w := 0.0
+	for _, line := range lines {
+		w = max(w, text.Advance(line, face))
+	}
+	return w, float64(len(lines)) * lineHeight(face)
+}
+
+// lines returns the text broken into the lines drawn at width (0 = unlimited).
+func (l *Label) lines(face text.Face, width float64) []string {
+	c := &l.cache[0]
+	if width > 0 {
+		c = &l.cache[1]
+	}
+	if c.lines != nil && c.text == l.Text && c.width == width && c.wrap == l.Wrap &&
+		c.maxLines == l.MaxLines && c.face == face {
+		return c.lines
+	}
+	*c = labelCache{text: l.Text, width: width, wrap: l.Wrap, maxLines: l.MaxLines, face: face}
+	c.lines = layoutLines(l.Text, face, width, l.Wrap, l.MaxLines)
+	return c.lines
+}
+
+// layoutLines splits s at line breaks, wraps each line to width when wrap is set, and
+// keeps at most maxLines lines (0 = all). Cut text is replaced by a single ellipsis.
+func layoutLines(s string, face text.Face, width float64, wrap bool, maxLines int) []string {
+	var lines []string
+	truncated := false
+	for _, para := range strings.Split(s, "\n") {
+		runes := []rune(para)
+		start := 0
+		var breaks []int
+		if wrap {
+			breaks = wrapRunes(runes, face, width)
+		}
+		for _, end := range append(breaks, len(runes)) {
+			lines = append(lines, strings.TrimRightFunc(string(runes[start:end]), unicode.IsSpace))
+			start = end
+		}
+		if maxLines > 0 && len(lines) > maxLines {
+			lines = lines[:maxLines]
+			truncated = true
+			break
+		}
+	}
+	for i, line := range lines {
+		switch {
+		case truncated && i == len(lines)-1:
+			// A line already ending in an ellipsis shows the cut as it is.
+			lines[i] = ellipsize(strings.TrimSuffix(line, labelEllipsis), face, width)
+		case !wrap && width > 0 && text.Advance(line, face) > width:
+			lines[i] = ellipsize(line, face, width)
+		}
+	}
+	return lines
+}
+
+// ellipsize shortens line until it fits in width with an ellipsis appended. A width of 0
+// means unlimited: the whole line is kept.
+func ellipsize(line string, face text.Face, width float64) string {
+	runes := []rune(line)
+	for {
+		s := string(runes) + labelEllipsis
+		if width <= 0 || len(runes) == 0 || text.Advance(s, face) <= width {
+			return s
+		}
+		runes = runes[:len(runes)-1]
+	}
+}
+
+// LabelTheme controls label drawing colors.
+type LabelTheme struct {
+	Text colors.Color
+}
+
+// DefaultLabelTheme returns the default label theme.
+func DefaultLabelTheme() LabelTheme {
+	return LabelTheme{
+		Text: colors.HexOr("#eee", colors.RGB(238, 238, 238)),
+	}
+}
+
+// Draw draws the aligned lines, clipped to the label bounds.
+func (l *Label) Draw(ctx *DrawContext) {
+	l.draw(ctx.Dst, ctx.FaceFor(l.Font), themeOr(l.Theme, ctx.Theme.Label))
+}
+
+func (l *Label) draw(dst *ebiten.Image, face text.Face, theme LabelTheme) {
+	b := l.Bounds()
+	clr := theme.Text
+	if l.Color != nil {
+		clr = *l.Color
+	}
+	clips := rendering.NewClipStack(dst)
+	target := clips.Push(b)
+	if !clips.Visible() {
+		return
+	}
+
+	lines := l.lines(face, b.W)
+	lineH := lineHeight(face)
+	y := b.Y + layout.AlignOffset(l.VerticalAlign, b.H, float64(len(lines))*lineH)
+	for _, line := range lines {
+		x := b.X + layout.AlignOffset(l.HorizontalAlign, b.W, text.Advance(line, face))
+		rendering.DrawText(target, line, face, int(x), int(y), clr)
+		y += lineH
+	}
+}
@@ -25,13 +25,62 @@ type MenuEntryKind int
 const (
 	MenuEntryItem MenuEntryKind = iota
 	MenuEntrySeparator
+	MenuEntryCheck // toggles Checked when activated
+	MenuEntryRadio // checked when activated, unchecking the other entries of its Group
 )
 
-// MenuEntry is a submenu row: either a clickable item or a separator.
+// MenuEntry is a submenu row: a clickable item, a check or radio item, or a separator. An
+// item with SubItems opens a nested submenu beside it instead of running OnClick.
 type MenuEntry struct {
-	Kind    MenuEntryKind
-	Label   string
-	OnClick func()
+	Kind     MenuEntryKind
+	Label    string
+	OnClick  func()
+	SubItems []MenuEntry
+
+	Checked  bool               // check mark of MenuEntryCheck and MenuEntryRadio entries
+	Group    string             // radio group, among the entries of the same submenu
+	OnCheck  func(checked bool) // called with the new Checked state before OnClick
+	Disabled bool               // greyed out; cannot be highlighted or run
+	Icon     *ebiten.Image      // drawn before the label; nil for none
+	Shortcut string             // accelerator drawn at the right, e.g. "Ctrl+S"; pressing it runs the entry
+}
+
+// AddEntry appends an entry to the nested submenu of the entry, e.g. a check item:
+// AddEntry(MenuEntry{Kind: MenuEntryCheck, Label: "Wrap", Shortcut: "Alt+Z"}).
+func (e *MenuEntry) AddEntry(entry MenuEntry) *MenuEntry {
+	e.SubItems = append(e.SubItems, entry)
+	return e
+}
+
+// AddSubItem appends a clickable item to the nested submenu of the entry.
+func (e *MenuEntry) AddSubItem(label string, onClick func()) *MenuEntry {
+	e.SubItems = append(e.SubItems, MenuEntry{
+		Kind:    MenuEntryItem,
+		Label:   label,
+		OnClick: onClick,
+	})
+	return e
+}
+
+// AddSeparator appends a separator to the nested submenu of the entry.
+func (e *MenuEntry) AddSeparator() *MenuEntry {
+	e.SubItems = append(e.SubItems, MenuEntry{Kind: MenuEntrySeparator})
+	return e
+}
+
+// AddSubMenu appends an item with a nested submenu, filled in by build.
+func (e *MenuEntry) AddSubMenu(label string, build func(sub *MenuEntry)) *MenuEntry {
+	e.SubItems = append(e.SubItems, newSubMenu(label, build))
+	return e
+}
+
+// newSubMenu returns an item whose nested submenu is filled in by build.
+func newSubMenu(label string, build func(sub *MenuEntry)) MenuEntry {
+	sub := MenuEntry{Kind: MenuEntryItem, Label: label}
+	if build != nil {
+		build(&sub)
+	}
+	return sub
 }
 
 // MenuItem is a top-level menu label and optional submenu.
@@ -57,16 +106,56 @@ func (m *MenuItem) AddSeparator() *MenuItem {
 	return m
 }
 
-// MenuBar is a horizontal menu strip with optional dropdown submenus.
+// AddEntry appends a submenu entry, e.g. an item with a shortcut:
+// AddEntry(MenuEntry{Label: "Save", Shortcut: "Ctrl+S", OnClick: save}).
+func (m *MenuItem) AddEntry(entry MenuEntry) *MenuItem {
+	m.SubItems = append(m.SubItems, entry)
+	return m
+}
+
+// AddSubMenu appends an item with a nested submenu, filled in by build, e.g.
+// AddSubMenu("Recent", func(sub *MenuEntry) { sub.AddSubItem("project.go", open) }).
+func (m *MenuItem) AddSubMenu(label string, build func(sub *MenuEntry)) *MenuItem {
+	m.SubItems = append(m.SubItems, newSubMenu(label, build))
+	return m
+}
+
+// MenuBar is a horizontal menu strip with optional dropdown submenus, which may nest.
+//
+// Labels may mark a mnemonic with "&", as in "&File" or "Save &As" ("&&" is a literal
+// "&"); the character is drawn underlined. Alt plus the mnemonic of a top-level item opens
+// its dropdown, and pressing Alt alone or F10 activates the bar from the keyboard, as does
+// Activate. While the bar is active or a dropdown is open it takes the keyboard:
+//   - Left and Right move between top-level items, and within a dropdown open and close
+//     nested submenus or move to the previous or next menu;
+//   - Down, Enter or Space open the highlighted menu; Up and Down move the highlight in the
+//     dropdown, and Enter or Space run the highlighted entry;
+//   - a letter or digit runs the entry with that mnemonic, or highlights the next entry
+//     starting with it (running it when it is the only one);
+//   - Escape closes the deepest submenu, then the dropdown, then deactivates the bar.
+//
+// Entry shortcuts and the Alt+mnemonic keys of top-level items are bound as global
+// accelerators (see Accelerators) and run their entry from anywhere in the UI, with the
+// dropdown closed. One that conflicts with an earlier binding is left out until that
+// binding is removed.
 type MenuBar struct {
-	ui        *UI
-	c         *layout.Container
+	Element
+	drop      *layout.Container // floating node for the open submenu
 	WidthMode MenuBarWidthMode
 	Items     []MenuItem
+	Font      *Font      // nil uses the UI default font
+	Theme     *MenuTheme // nil uses the UI theme
 
 	openIndex int
 	hoverTop  int
-	hoverSub  int
+	keyTop    int          // top-level item highlighted from the keyboard, or -1
+	sub       *menuCascade // open levels of the dropdown
+
+	shortcuts    menuShortcuts // entry shortcuts and mnemonics bound in the UI accelerators
+	shortcutPath []int         // reused by walkShortcuts
+
+	widths    map[string]float64 // measured label widths in widthFace
+	widthFace text.Face
 }
 
 // NewMenuBar creates a standalone menu bar (not in the tree).
@@ -75,20 +164,30 @@ func NewMenuBar(height layout.Size, widthMode MenuBarWidthMode) *MenuBar {
 	if widthMode == MenuBarWidthFull {
 		width = layout.PercentOf(100)
 	}
-	return &MenuBar{
-		c:         layout.NewContainer(width, height),
+	m := &MenuBar{
 		WidthMode: widthMode,
 		openIndex: -1,
 		hoverTop:  -1,
-		hoverSub:  -1,
+		keyTop:    -1,
+		widths:    make(map[string]float64),
 	}
+	m.drop = layout.NewContainer(layout.FitSize(), layout.FitSize())
+	m.drop.Floating = &layout.Floating{
+		Element:         layout.AttachTopLeft,
+		Target:          layout.AttachBottomLeft,
+		ClampToViewport: true,
+	}
+	m.drop.Measure = m.measureDropdown
+	m.sub = newMenuCascade(m.drop, m.rows, m.rowHeight, m.levelWidth)
+	m.Init(layout.NewContainer(width, height))
+	drop := &menuDropdown{m: m}
+	drop.Init(m.drop)
+	m.AddChild(drop)
+	return m
 }
 
-// Container returns the layout node for this menu bar (internal use).
-func (m *MenuBar) Container() *layout.Container { return m.c }
-
-// Bounds returns the computed layout rect after Layout.
-func (m *MenuBar) Bounds() layout.Rect { return m.c.Bounds }
+// DropdownContainer returns the floating layout node of the open submenu (internal use).
+func (m *MenuBar) DropdownContainer() *layout.Container { return m.drop }
 
 // AddItem appends a top-level menu item.
 func (m *MenuBar) AddItem(label string, onClick func()) *MenuItem {
@@ -96,8 +195,8 @@ func (m *MenuBar) AddItem(label string, onClick func()) *MenuItem {
 	return &m.Items[len(m.Items)-1]
 }
 
-// IsOpen reports whether any submenu is currently open.
-func (m *MenuBar) IsOpen() bool { return m.openIndex >= 0 }
+// IsOpen reports whether a dropdown is open or the bar is active from the keyboard.
+func (m *MenuBar) IsOpen() bool { return m.openIndex >= 0 || m.keyTop >= 0 }
 
 // OpenIndex returns the currently open top-level item index, or -1.
 func (m *MenuBar) OpenIndex() int { return m.openIndex }
@@ -105,24 +204,94 @@ func (m *MenuBar) OpenIndex() int { return m.openIndex }
 // HoverTopIndex returns the top-level hovered index, or -1.
 func (m *MenuBar) HoverTopIndex() int { return m.hoverTop }
 
-// HoverSubIndex returns the hovered submenu index, or -1.
-func (m *MenuBar) HoverSubIndex() int { return m.hoverSub }
+// HoverSubIndex returns the highlighted entry of the first dropdown level, or -1.
+func (m *MenuBar) HoverSubIndex() int {
+	if m.openIndex < 0 {
+		return -1
+	}
+	return m.sub.active(0)
+}
 
-// Close closes any open submenu.
+// Close closes any open submenu and deactivates the bar.
 func (m *MenuBar) Close() {
 	m.openIndex = -1
-	m.hoverSub = -1
+	m.keyTop = -1
+	m.sub.reset()
+	if m.ui != nil && m.ui.menu == Widget(m) {
+		m.ui.menu = nil
+	}
+}
+
+// open opens the dropdown of a top-level item, which takes keyboard events while open.
+func (m *MenuBar) open(index int) {
+	m.openIndex = index
+	m.keyTop = -1
+	m.sub.reset()
+	if m.ui != nil {
+		m.ui.menu = m
+	}
 }
 
-// SyncWidth updates layout width based on width mode.
+// Activate highlights the first top-level item and gives the bar the keyboard, as pressing
+// Alt or F10 does.
+func (m *MenuBar) Activate() {
+	if len(m.Items) > 0 {
+		m.arm(0)
+	}
+}
+
+// arm closes the dropdown and highlights a top-level item from the keyboard. The bar keeps
+// the keyboard until Close.
+func (m *MenuBar) arm(index int) {
+	m.openIndex = -1
+	m.sub.reset()
+	m.keyTop = index
+	if m.ui != nil {
+		m.ui.menu = m
+	}
+}
+
+// openFromKeys opens the dropdown of a top-level item with its first entry highlighted,
+// or runs the item when it has no dropdown.
+func (m *MenuBar) openFromKeys(index int) {
+	item := m.Items[index]
+	if len(item.SubItems) == 0 {
+		m.Close()
+		if item.OnClick != nil {
+			item.OnClick()
+		}
+		return
+	}
+	m.open(index)
+	m.sub.step(1)
+}
+
+// topRows describes the top-level items for mnemonic and typeahead matching.
+func (m *MenuBar) topRows() []menuRow {
+	rows := make([]menuRow, len(m.Items))
+	for i, it := range m.Items {
+		label, mnemonic := parseMnemonic(it.Label)
+		rows[i] = menuRow{label: label, mnemonic: mnemonic}
+	}
+	return rows
+}
+
+// SyncWidth updates layout width based on width mode and attaches the open submenu
+// under its top-level item. UI.Layout calls it before laying out the tree.
 func (m *MenuBar) SyncWidth() {
+	m.drop.Floating.OffsetX = menuBarPaddingX
+	for i := 0; i < m.openIndex && i < len(m.Items); i++ {
+		m.drop.Floating.OffsetX += m.topItemWidth(m.Items[i].Label)
+	}
+	m.sub.sync()
+
 	if m.WidthMode == MenuBarWidthFull {
 		m.c.Width = layout.PercentOf(100)
 		return
 	}
 	w := menuBarPaddingX * 2
 	for _, it := range m.Items {
-		w += menuTopItemWidth(it.Label)
+		w += m.topItemWidth(it.Label)
 	}
 	if w < 40 {
 		w = 40
@@ -137,53 +306,70 @@ func (m *MenuBar) TopItemRects() []layout.Rect {
 	y := m.c.Bounds.Y
 	h := m.c.Bounds.H
 	for _, it := range m.Items {
-		w := menuTopItemWidth(it.Label)
+		w := m.topItemWidth(it.Label)
 		out = append(out, layout.Rect{X: x, Y: y, W: w, H: h})
 		x += w
 	}
 	return out
 }
 
-// OpenSubItemRects returns rects for the currently open submenu entries.
+// OpenSubItemRects returns rects for the entries of the first open dropdown level.
 func (m *MenuBar) OpenSubItemRects() []layout.Rect {
-	if m.openIndex < 0 || m.openIndex >= len(m.Items) {
+	if m.openIndex < 0 {
 		return nil
 	}
-	top := m.TopItemRects()
-	if m.openIndex >= len(top) {
-		return nil
+	return m.sub.rowRects(0)
+}
+
+// OpenSubMenuBounds returns the bounds of the first open dropdown level.
+func (m *MenuBar) OpenSubMenuBounds() layout.Rect {
+	if len(m.OpenSubItemRects()) == 0 {
+		return layout.Rect{}
 	}
-	item := m.Items[m.openIndex]
-	if len(item.SubItems) == 0 {
+	return m.sub.bounds(0)
+}
+
+// entries returns the entries of the dropdown level reached through path, or nil.
+func (m *MenuBar) entries(path []int) []MenuEntry {
+	if m.openIndex < 0 || m.openIndex >= len(m.Items) {
 		return nil
 	}
-	dropW := m.openDropdownWidth(item)
-	x := top[m.openIndex].X
-	y := m.c.Bounds.Y + m.c.Bounds.H
-
-	rects := make([]layout.Rect, 0, len(item.SubItems))
-	for _, ent := range item.SubItems {
-		h := menuSubItemHeight
-		if ent.Kind == MenuEntrySeparator {
-			h = menuSubSeparatorHeight
+	entries := m.Items[m.openIndex].SubItems
+	for _, i := range path {
+		if i < 0 || i >= len(entries) {
+			return nil
 		}
-		rects = append(rects, layout.Rect{X: x, Y: y, W: dropW, H: h})
-		y += h
+		entries = entries[i].SubItems
 	}
-	return rects
+	return entries
 }
 
-// OpenSubMenuBounds returns the full dropdown bounds for the currently open submenu.
-func (m *MenuBar) OpenSubMenuBounds() layout.Rect {
-	rects := m.OpenSubItemRects()
-	if len(rects) == 0 {
-		return layout.Rect{}
+// rows describes a dropdown level to the cascade.
+func (m *MenuBar) rows(path []int) []menuRow {
+	entries := m.entries(path)
+	rows := make([]menuRow, len(entries))
+	for i, ent := range entries {
+		label, mnemonic := parseMnemonic(ent.Label)
+		rows[i] = menuRow{
+			separator: ent.Kind == MenuEntrySeparator,
+			disabled:  ent.Disabled,
+			submenu:   len(ent.SubItems) > 0,
+			label:     label,
+			mnemonic:  mnemonic,
+			shortcut:  ent.Shortcut,
+			icon:      ent.Icon,
+			mark:      menuEntryMark(ent.Kind),
+			checked:   ent.Checked,
+		}
 	}
-	var h float64
-	for _, r := range rects {
-		h += r.H
+	return rows
+}
+
+func (m *MenuBar) rowHeight(r menuRow) float64 {
+	if r.separator {
+		return menuSubSeparatorHeight
 	}
-	return layout.Rect{X: rects[0].X, Y: rects[0].Y, W: rects[0].W, H: h}
+	return m.subItemHeight()
 }
 
 // OnMouseMove updates hover state. If a submenu is open, moving across top
@@ -192,13 +378,11 @@ func (m *MenuBar) OnMouseMove(x, y float64) {
 	m.hoverTop = m.hitTopItem(x, y)
 	if m.openIndex >= 0 && m.hoverTop >= 0 && m.hoverTop != m.openIndex {
 		if len(m.Items[m.hoverTop].SubItems) > 0 {
-			m.openIndex = m.hoverTop
+			m.open(m.hoverTop)
 		}
 	}
 	if m.openIndex >= 0 {
-		m.hoverSub = m.hitSubItem(x, y, false)
-	} else {
-		m.hoverSub = -1
+		m.sub.pointerMove(x, y)
 	}
 }
 
@@ -217,23 +401,14 @@ func (m *MenuBar) OnMouseDown(x, y float64) bool {
 		if m.openIndex == top {
 			m.Close()
 		} else {
-			m.openIndex = top
-			m.hoverSub = -1
+			m.open(top)
 		}
 		return true
 	}
 
 	if m.openIndex >= 0 {
-		sub := m.hitSubItem(x, y, true)
-		if sub >= 0 {
-			ent := m.Items[m.openIndex].SubItems[sub]
-			if ent.Kind == MenuEntryItem {
-				if ent.OnClick != nil {
-					ent.OnClick()
-				}
-				m.Close()
-				return true
-			}
+		if level, row := m.sub.hit(x, y); level >= 0 {
+			m.activate(level, row)
 			return true
 		}
 		m.Close()
@@ -241,89 +416,293 @@ func (m *MenuBar) OnMouseDown(x, y float64) bool {
 	return false
 }
 
-func (m *MenuBar) openDropdownWidth(item MenuItem) float64 {
-	w := menuSubContentPaddingX * 2
-	for _, ent := range item.SubItems {
-		if ent.Kind == MenuEntryItem {
-			tw := menuTextWidth(ent.Label)
-			if tw+menuSubContentPaddingX*2 > w {
-				w = tw + menuSubContentPaddingX*2
+func menuEntryMark(kind MenuEntryKind) menuMark {
+	switch kind {
+	case MenuEntryCheck:
+		return menuMarkCheck
+	case MenuEntryRadio:
+		return menuMarkRadio
+	}
+	return menuMarkNone
+}
+
+// activate runs a dropdown entry and closes the dropdown, or opens the entry's nested
+// submenu. Separators and disabled entries do nothing.
+func (m *MenuBar) activate(level, row int) {
+	entries := m.entries(m.sub.path[:level])
+	ent := entries[row]
+	switch {
+	case ent.Kind == MenuEntrySeparator || ent.Disabled:
+	case len(ent.SubItems) > 0:
+		m.sub.open(level, row)
+	default:
+		runMenuEntry(entries, row)
+		m.Close()
+	}
+}
+
+// runMenuEntry runs entries[i]: a check entry toggles, a radio entry is checked and the
+// other entries of its group unchecked, then OnCheck and OnClick are called.
+func runMenuEntry(entries []MenuEntry, i int) {
+	ent := &entries[i]
+	switch ent.Kind {
+	case MenuEntryCheck:
+		ent.Checked = !ent.Checked
+	case MenuEntryRadio:
+		for j := range entries {
+			if entries[j].Kind == MenuEntryRadio && entries[j].Group == ent.Group {
+				entries[j].Checked = j == i
 			}
 		}
 	}
-	if w < 120 {
-		w = 120
+	if ent.OnCheck != nil && ent.Kind != MenuEntryItem {
+		ent.OnCheck(ent.Checked)
+	}
+	if ent.OnClick != nil {
+		ent.OnClick()
 	}
-	return w
 }
 
-func (m *MenuBar) hitTopItem(x, y float64) int {
-	for i, r := range m.TopItemRects() {
-		if rendering.PointWithinBounds(x, y, r) {
-			return i
+// walkShortcuts visits the shortcuts of the dropdown entries, with paths starting at the
+// top-level item, then the Alt+mnemonic keys of the top-level items, with paths of one
+// index.
+func (m *MenuBar) walkShortcuts(visit func(keys Shortcut, path []int)) {
+	path := m.shortcutPath[:0]
+	var walkEntries func(entries []MenuEntry)
+	walkEntries = func(entries []MenuEntry) {
+		for i, ent := range entries {
+			path = append(path, i)
+			if len(ent.SubItems) > 0 {
+				walkEntries(ent.SubItems)
+			} else if ent.Shortcut != "" {
+				if keys, ok := m.shortcuts.parse(ent.Shortcut); ok {
+					visit(keys, path)
+				}
+			}
+			path = path[:len(path)-1]
 		}
 	}
-	return -1
+	for i, it := range m.Items {
+		path = append(path[:0], i)
+		walkEntries(it.SubItems)
+	}
+	for i, it := range m.Items {
+		if key, ok := runeKey(mnemonicOf(it.Label)); ok {
+			visit(Shortcut{Key: key, Mods: ModAlt}, append(path[:0], i))
+		}
+	}
+	m.shortcutPath = path
 }
 
-func (m *MenuBar) hitSubItem(x, y float64, includeSeparator bool) int {
-	rects := m.OpenSubItemRects()
-	if len(rects) == 0 || m.openIndex < 0 || m.openIndex >= len(m.Items) {
-		return -1
+// entryAt returns the dropdown entry at path (a top-level item, then entry indices) as the
+// slice holding it and its index, or nil when the path no longer exists or passes through
+// a separator or disabled entry.
+func (m *MenuBar) entryAt(path []int) ([]MenuEntry, int) {
+	if path[0] >= len(m.Items) {
+		return nil, 0
+	}
+	entries := m.Items[path[0]].SubItems
+	for depth, i := range path[1:] {
+		if i >= len(entries) || entries[i].Kind == MenuEntrySeparator || entries[i].Disabled {
+			return nil, 0
+		}
+		if depth == len(path)-2 {
+			return entries, i
+		}
+		entries = entries[i].SubItems
+	}
+	return nil, 0
+}
+
+// runShortcut opens the top-level item of a mnemonic path, or closes the dropdown and runs
+// the entry at path.
+func (m *MenuBar) runShortcut(path []int) {
+	if len(path) == 1 {
+		m.openFromKeys(path[0])
+		return
+	}
+	if entries, i := m.entryAt(path); entries != nil {
+		m.Close()
+		runMenuEntry(entries, i)
+	}
+}
+
+// shortcutEnabled reports whether the shortcut of path can run.
+func (m *MenuBar) shortcutEnabled(path []int) bool {
+	if len(path) == 1 {
+		return path[0] < len(m.Items)
+	}
+	entries, _ := m.entryAt(path)
+	return entries != nil
+}
+
+// handleKey navigates the active bar or the open dropdown. Left and Right past the nested
+// submenus move to the previous or next top-level menu with a dropdown.
+func (m *MenuBar) handleKey(e *Event) {
+	switch {
+	case e.Key == ebiten.KeyF10 && e.Mods == 0:
+		m.Close()
+	case m.openIndex < 0:
+		m.handleBarKey(e)
+	case e.Key == ebiten.KeyEscape:
+		if !m.sub.back() {
+			m.arm(m.openIndex)
+		}
+	case isActivation(e):
+		m.activateSelected()
+	case m.sub.handleKey(e.Key):
+	case e.Key == ebiten.KeyArrowLeft:
+		m.openAdjacent(-1)
+	case e.Key == ebiten.KeyArrowRight:
+		m.openAdjacent(1)
+	case isTypeahead(e):
+		if row, only := m.sub.typeahead(keyRune(e.Key)); row >= 0 && only {
+			m.activateSelected()
+		}
+	}
+}
+
+// handleBarKey moves between the top-level items of the active bar and opens them.
+func (m *MenuBar) handleBarKey(e *Event) {
+	n := len(m.Items)
+	switch {
+	case e.Key == ebiten.KeyEscape:
+		m.Close()
+	case e.Key == ebiten.KeyArrowLeft:
+		m.keyTop = (m.keyTop - 1 + n) % n
+	case e.Key == ebiten.KeyArrowRight:
+		m.keyTop = (m.keyTop + 1) % n
+	case isActivation(e):
+		m.openFromKeys(m.keyTop)
+	case e.Key == ebiten.KeyArrowDown, e.Key == ebiten.KeyArrowUp:
+		if len(m.Items[m.keyTop].SubItems) > 0 {
+			m.openFromKeys(m.keyTop)
+		}
+	case isTypeahead(e):
+		matches := menuMatches(m.topRows(), keyRune(e.Key))
+		if len(matches) == 0 {
+			return
+		}
+		m.keyTop = nextMatch(matches, m.keyTop)
+		if len(matches) == 1 {
+			m.openFromKeys(m.keyTop)
+		}
+	}
+}
+
+// activateSelected opens the submenu of the highlighted entry of the deepest level, or
+// runs the entry.
+func (m *MenuBar) activateSelected() {
+	if !m.sub.openHovered() {
+		if level, row := m.sub.selected(); level >= 0 {
+			m.activate(level, row)
+		}
+	}
+}
+
+// openAdjacent opens the next top-level menu with a dropdown in direction dir, wrapping
+// around, and highlights its first entry.
+func (m *MenuBar) openAdjacent(dir int) {
+	n := len(m.Items)
+	for i := 1; i < n; i++ {
+		index := ((m.openIndex+dir*i)%n + n) % n
+		if len(m.Items[index].SubItems) > 0 {
+			m.open(index)
+			m.sub.step(1)
+			return
+		}
+	}
+}
+
+// measureDropdown reports the open submenu size for the floating dropdown node.
+func (m *MenuBar) measureDropdown(float64) (float64, float64) {
+	if m.openIndex < 0 {
+		return 0, 0
 	}
-	for i, r := range rects {
+	return m.sub.measure(0)
+}
+
+// levelWidth returns the width of the dropdown level reached through path.
+func (m *MenuBar) levelWidth(path []int) float64 {
+	return menuLevelWidth(m.rows(path), m.textWidth, 120)
+}
+
+func (m *MenuBar) hitTopItem(x, y float64) int {
+	for i, r := range m.TopItemRects() {
 		if rendering.PointWithinBounds(x, y, r) {
-			if !includeSeparator && m.Items[m.openIndex].SubItems[i].Kind == MenuEntrySeparator {
-				return -1
-			}
 			return i
 		}
 	}
 	return -1
 }
 
-
 const (
 	menuBarPaddingX        = 8.0
 	menuTopPaddingX        = 8.0
-	menuSubContentPaddingX = 10.0
-	menuSubItemHeight      = 22.0
+	menuSubPaddingY        = 3.0
+	menuSubItemHeight      = 22.0 // minimum submenu row height
 	menuSubSeparatorHeight = 8.0
-	menuCharWidth          = 8.0
 )
 
-func menuTopItemWidth(label string) float64 {
-	return menuTextWidth(label) + menuTopPaddingX*2
+func (m *MenuBar) topItemWidth(label string) float64 {
+	label, _ = parseMnemonic(label)
+	return m.textWidth(label) + menuTopPaddingX*2
+}
+
+// textWidth returns the width of label in the face the menu is drawn with. Widths are
+// cached per label and measured again when the face changes (other font or size).
+func (m *MenuBar) textWidth(label string) float64 {
+	face := m.ui.faceFor(m.Font)
+	if face == nil {
+		return 0
+	}
+	if face != m.widthFace {
+		clear(m.widths)
+		m.widthFace = face
+	}
+	w, ok := m.widths[label]
+	if !ok {
+		w = text.Advance(label, face)
+		m.widths[label] = w
+	}
+	return w
 }
 
-func menuTextWidth(label string) float64 {
-	return float64(len([]rune(label))) * menuCharWidth
+// subItemHeight returns the height of a submenu row: a line of text plus padding.
+func (m *MenuBar) subItemHeight() float64 {
+	face := m.ui.faceFor(m.Font)
+	if face == nil {
+		return menuSubItemHeight
+	}
+	return max(menuSubItemHeight, lineHeight(face)+menuSubPaddingY*2)
 }
 
 // MenuTheme controls menu bar and dropdown colors.
 type MenuTheme struct {
-	Fill      colors.Color
-	Stroke    colors.Color
-	Hover     colors.Color
-	Active    colors.Color
-	Text      colors.Color
-	Separator colors.Color
+	Fill         colors.Color
+	Stroke       colors.Color
+	Hover        colors.Color
+	Active       colors.Color
+	Text         colors.Color
+	DisabledText colors.Color
+	Separator    colors.Color
 }
 
 // DefaultMenuTheme returns the default menu color theme.
 func DefaultMenuTheme() MenuTheme {
 	return MenuTheme{
-		Fill:      colors.HexOr("#202020", colors.RGB(32, 32, 32)),
-		Stroke:    colors.HexOr("#525252", colors.RGB(82, 82, 82)),
-		Hover:     colors.HexOr("#2f2f2f", colors.RGB(47, 47, 47)),
-		Active:    colors.HexOr("#3a3a3a", colors.RGB(58, 58, 58)),
-		Text:      colors.HexOr("#f0f0f0", colors.RGB(240, 240, 240)),
-		Separator: colors.HexOr("#606060", colors.RGB(96, 96, 96)),
+		Fill:         colors.HexOr("#202020", colors.RGB(32, 32, 32)),
+		Stroke:       colors.HexOr("#525252", colors.RGB(82, 82, 82)),
+		Hover:        colors.HexOr("#2f2f2f", colors.RGB(47, 47, 47)),
+		Active:       colors.HexOr("#3a3a3a", colors.RGB(58, 58, 58)),
+		Text:         colors.HexOr("#f0f0f0", colors.RGB(240, 240, 240)),
+		DisabledText: colors.HexOr("#7a7a7a", colors.RGB(122, 122, 122)),
+		Separator:    colors.HexOr("#606060", colors.RGB(96, 96, 96)),
 	}
 }
 
 // DrawBar draws the menu strip and top-level items.
-func (m *MenuBar) DrawBar(dst *ebiten.Image, face text.GoTextFace, theme MenuTheme) {
+func (m *MenuBar) DrawBar(dst *ebiten.Image, face text.Face, theme MenuTheme) {
 	mb := m.Bounds()
 	rendering.FillRect(dst, mb.X, mb.Y, mb.W, mb.H, theme.Fill)
 	rendering.DrawStrokeRect(dst, mb.X, mb.Y, mb.W, mb.H, 1.0, theme.Stroke)
@@ -333,46 +712,94 @@ func (m *MenuBar) DrawBar(dst *ebiten.Image, face text.GoTextFace, theme MenuThe
 		if m.HoverTopIndex() == i {
 			rendering.FillRect(dst, r.X, r.Y, r.W, r.H, theme.Hover)
 		}
-		if m.OpenIndex() == i {
+		if m.OpenIndex() == i || m.keyTop == i {
 			rendering.FillRect(dst, r.X, r.Y, r.W, r.H, theme.Active)
 		}
-		textY := textTopY(m.Items[i].Label, face, r.Y, r.H)
-		rendering.DrawText(dst, m.Items[i].Label, face, int(r.X)+8, textY, theme.Text)
+		label, mnemonic := parseMnemonic(m.Items[i].Label)
+		textY := textTopY(label, face, r.Y, r.H)
+		rendering.DrawText(dst, label, face, int(r.X+menuTopPaddingX), textY, theme.Text)
+		drawMnemonic(dst, label, mnemonic, face, r.X+menuTopPaddingX, float64(textY), theme.Text)
 	}
 }
 
-// DrawDropdown draws the currently open dropdown, if any.
-func (m *MenuBar) DrawDropdown(dst *ebiten.Image, face text.GoTextFace, theme MenuTheme) {
-	if !m.IsOpen() {
+// DrawDropdown draws the open dropdown levels, if any.
+func (m *MenuBar) DrawDropdown(dst *ebiten.Image, face text.Face, theme MenuTheme) {
+	if m.openIndex < 0 {
 		return
 	}
-	drop := m.OpenSubMenuBounds()
-	if drop.W > 0 && drop.H > 0 {
-		rendering.FillRect(dst, drop.X, drop.Y, drop.W, drop.H, theme.Fill)
-		rendering.DrawStrokeRect(dst, drop.X, drop.Y, drop.W, drop.H, 1.0, theme.Stroke)
+	clr := menuColors{
+		fill:      theme.Fill,
+		stroke:    theme.Stroke,
+		hover:     theme.Hover,
+		text:      theme.Text,
+		disabled:  theme.DisabledText,
+		separator: theme.Separator,
 	}
-
-	subRects := m.OpenSubItemRects()
-	open := m.OpenIndex()
-	if open < 0 || open >= len(m.Items) {
-		return
+	for level := range m.sub.depth() {
+		m.sub.drawLevel(dst, face, level, clr)
 	}
-	subItems := m.Items[open].SubItems
-	for i, r := range subRects {
-		if i >= len(subItems) {
-			break
+}
+
+func (m *MenuBar) syncLayout() {
+	m.SyncWidth()
+	m.shortcuts.sync(m.ui, nil, m.walkShortcuts, m.runShortcut, m.shortcutEnabled)
+}
+
+// Draw draws the menu strip; the open submenu is a floating child widget.
+func (m *MenuBar) Draw(ctx *DrawContext) {
+	m.DrawBar(ctx.Dst, ctx.FaceFor(m.Font), themeOr(m.Theme, ctx.Theme.Menu))
+}
+
+// HandleEvent updates hover state, handles clicks on the top-level items and navigates the
+// active bar and the open dropdown with the keyboard.
+func (m *MenuBar) HandleEvent(e *Event) {
+	switch e.Type {
+	case EventMouseMove:
+		m.OnMouseMove(e.X, e.Y)
+	case EventMouseLeave:
+		m.hoverTop = -1
+		m.sub.leave()
+	case EventMouseDown:
+		if e.isPrimaryPress() && m.OnMouseDown(e.X, e.Y) {
+			e.StopPropagation()
 		}
-		entry := subItems[i]
-		if entry.Kind == MenuEntrySeparator {
-			y := r.Y + r.H/2
-			rendering.FillRect(dst, r.X+6, y, r.W-12, 1, theme.Separator)
-			continue
+	case EventKeyDown:
+		if m.IsOpen() {
+			m.handleKey(e)
+			e.StopPropagation()
 		}
-		if m.HoverSubIndex() == i {
-			rendering.FillRect(dst, r.X, r.Y, r.W, r.H, theme.Hover)
+	case EventKeyUp:
+		// Releasing Alt goes on to the UI, which deactivates the bar after a lone Alt press.
+		if m.IsOpen() && e.Key != ebiten.KeyAlt {
+			e.StopPropagation()
+		}
+	case EventTextInput:
+		if m.IsOpen() {
+			e.StopPropagation()
 		}
-		textY := textTopY(entry.Label, face, r.Y, r.H)
-		rendering.DrawText(dst, entry.Label, face, int(r.X)+10, textY, theme.Text)
 	}
 }
 
+// menuDropdown is the floating widget showing the open submenu of a MenuBar.
+type menuDropdown struct {
+	Element
+	m *MenuBar
+}
+
+func (d *menuDropdown) Draw(ctx *DrawContext) {
+	d.m.DrawDropdown(ctx.Dst, ctx.FaceFor(d.m.Font), themeOr(d.m.Theme, ctx.Theme.Menu))
+}
+
+func (d *menuDropdown) HitTest(x, y float64) bool {
+	return d.m.openIndex >= 0 && d.m.sub.contains(x, y)
+}
+
+func (d *menuDropdown) HandleEvent(e *Event) {
+	if e.Type != EventMouseDown {
+		return
+	}
+	if e.Button == ebiten.MouseButtonLeft {
+		d.m.OnMouseDown(e.X, e.Y)
+	}
+	e.StopPropagation()
+}
@@ -17,8 +17,10 @@ type RadioOption struct {
 
 // RadioGroup is a group of mutually exclusive radio buttons.
 type RadioGroup struct {
-	c             *layout.Container
+	Element
 	Options       []RadioOption
+	Font          *Font       // nil uses the UI default font
+	Theme         *RadioTheme // nil uses the UI theme
 	SelectedIndex int
 	OnChanged     func(int, string)
 	itemHeight    float64
@@ -27,26 +29,38 @@ type RadioGroup struct {
 
 // NewRadioGroup creates a standalone radio group. Add it with panel.AddRadioGroup(rg).
 func NewRadioGroup(width, height layout.Size, options []RadioOption) *RadioGroup {
-	return &RadioGroup{
-		c:             layout.NewContainer(width, height),
+	rg := &RadioGroup{
 		Options:       options,
 		SelectedIndex: -1,
 		itemHeight:    24.0,
 		hoveredIndex:  -1,
 	}
+	rg.Init(layout.NewContainer(width, height))
+	rg.SetFocusable(true)
+	rg.c.Measure = rg.measure
+	return rg
 }
 
-// Bounds returns the computed layout rect after Layout.
-func (rg *RadioGroup) Bounds() layout.Rect { return rg.c.Bounds }
-
-// Container returns the layout node for this radio group (internal use).
-func (rg *RadioGroup) Container() *layout.Container { return rg.c }
-
 // SetItemHeight sets the height of each radio option.
 func (rg *RadioGroup) SetItemHeight(height float64) {
 	rg.itemHeight = height
 }
 
+const (
+	radioCircleSize = 14.0
+	radioLabelGap   = 8.0
+)
+
+// measure reports the widest option and the stacked option height for Fit sizing.
+func (rg *RadioGroup) measure(float64) (float64, float64) {
+	var labelW float64
+	for _, opt := range rg.Options {
+		tw, _ := measureText(rg.ui, rg.Font, opt.Label)
+		labelW = max(labelW, tw)
+	}
+	return radioCircleSize + radioLabelGap + labelW, rg.itemHeight * float64(len(rg.Options))
+}
+
 // RadioTheme controls radio group drawing colors.
 type RadioTheme struct {
 	CircleFill   colors.Color
@@ -67,9 +81,14 @@ func DefaultRadioTheme() RadioTheme {
 	}
 }
 
-func (rg *RadioGroup) Draw(dst *ebiten.Image, face text.GoTextFace, theme RadioTheme) {
+// Draw draws every option with its radio circle.
+func (rg *RadioGroup) Draw(ctx *DrawContext) {
+	rg.draw(ctx.Dst, ctx.FaceFor(rg.Font), themeOr(rg.Theme, ctx.Theme.Radio))
+}
+
+func (rg *RadioGroup) draw(dst *ebiten.Image, face text.Face, theme RadioTheme) {
 	bound := rg.Bounds()
-	circleSize := 14.0
+	circleSize := radioCircleSize
 	circleRadius := circleSize / 2
 
 	for i, opt := range rg.Options {
@@ -89,14 +108,14 @@ func (rg *RadioGroup) Draw(dst *ebiten.Image, face text.GoTextFace, theme RadioT
 		if i == rg.hoveredIndex {
 			rendering.DrawFilledCircle(dst, circleCenterX, circleCenterY, circleRadius, theme.HoverOverlay)
 		}
-		labelX := int(bound.X + circleSize + 8)
+		labelX := int(bound.X + circleSize + radioLabelGap)
 		labelY := textTopY(opt.Label, face, y, rg.itemHeight)
 		rendering.DrawText(dst, opt.Label, face, labelX, labelY, theme.Text)
 	}
 }
 
-// HitTest returns the index of the option at the given point, or -1.
-func (rg *RadioGroup) HitTest(x, y float64) int {
+// HitTestOption returns the index of the option at the given point, or -1.
+func (rg *RadioGroup) HitTestOption(x, y float64) int {
 	bound := rg.Bounds()
 	if x < bound.X || x >= bound.X+bound.W {
 		return -1
@@ -125,3 +144,36 @@ func (rg *RadioGroup) Select(index int) {
 		rg.OnChanged(index, rg.Options[index].Value)
 	}
 }
+
+// HandleEvent tracks the hovered option and selects the option that is pressed.
+// While focused, the arrow keys select the previous or next option, wrapping around.
+func (rg *RadioGroup) HandleEvent(e *Event) {
+	switch e.Type {
+	case EventMouseMove:
+		rg.SetHovered(rg.HitTestOption(e.X, e.Y))
+	case EventMouseLeave:
+		rg.SetHovered(-1)
+	case EventMouseDown:
+		if index := rg.HitTestOption(e.X, e.Y); index >= 0 && e.isPrimaryPress() {
+			rg.Select(index)
+		}
+	case EventKeyDown:
+		n := len(rg.Options)
+		if n == 0 {
+			return
+		}
+		switch e.Key {
+		case ebiten.KeyArrowUp, ebiten.KeyArrowLeft:
+			if rg.SelectedIndex <= 0 {
+				rg.Select(n - 1)
+			} else {
+				rg.Select(rg.SelectedIndex - 1)
+			}
+		case ebiten.KeyArrowDown, ebiten.KeyArrowRight:
+			rg.Select((rg.SelectedIndex + 1) % n)
+		default:
+			return
+		}
+		e.StopPropagation()
+	}
+}
@@ -0,0 +1,332 @@
+package components
+
+import (
+	"goak/internal/goak/colors"
+	"goak/internal/goak/layout"
+	"goak/internal/goak/rendering"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// scrollAxis identifies which scrollbar is involved in a drag.
+type scrollAxis int
+
+const (
+	scrollNone scrollAxis = iota
+	scrollVertical
+	scrollHorizontal
+)
+
+// ScrollPanel is a panel whose children scroll inside its bounds. It shows vertical and
+// horizontal scrollbars when the content is larger than the panel, scrolls with the mouse
+// wheel (Shift+wheel for horizontal) and supports dragging the scrollbar thumbs.
+// All Panel methods (CreateButton, SetPadding, ...) are available.
+type ScrollPanel struct {
+	*Panel
+	ScrollStep  float64      // pixels scrolled per wheel notch
+	ScrollTheme *ScrollTheme // nil uses the UI theme
+
+	dragAxis   scrollAxis
+	dragOffset float64 // cursor position within the thumb when the drag started
+	hoverAxis  scrollAxis
+}
+
+// NewScrollPanel creates a standalone scroll panel. Add it with parent.AddScrollPanel(sp).
+func NewScrollPanel(width, height layout.Size) *ScrollPanel {
+	p := NewPanel(width, height)
+	p.c.Overflow = layout.OverflowScroll
+	return &ScrollPanel{Panel: p, ScrollStep: 40}
+}
+
+// ContentSize returns the size of the scrolled content, including padding, after Layout.
+func (sp *ScrollPanel) ContentSize() (w, h float64) {
+	return sp.c.ContentW, sp.c.ContentH
+}
+
+// ScrollOffset returns the current horizontal and vertical scroll offsets.
+func (sp *ScrollPanel) ScrollOffset() (x, y float64) {
+	return sp.c.ScrollX, sp.c.ScrollY
+}
+
+// SetScrollOffset sets the scroll offsets. Values are clamped to the content on the next Layout.
+func (sp *ScrollPanel) SetScrollOffset(x, y float64) {
+	sp.c.ScrollX = max(0, x)
+	sp.c.ScrollY = max(0, y)
+}
+
+// ScrollBy moves the scroll offsets by dx, dy.
+func (sp *ScrollPanel) ScrollBy(dx, dy float64) {
+	sp.SetScrollOffset(sp.c.ScrollX+dx, sp.c.ScrollY+dy)
+}
+
+// ScrollTo scrolls the least amount needed to bring r (in current layout coordinates,
+// e.g. a child's Bounds) into view.
+func (sp *ScrollPanel) ScrollTo(r layout.Rect) {
+	b := sp.Bounds()
+	x, y := sp.ScrollOffset()
+	x += scrollIntoView(r.X, r.W, b.X, b.W)
+	y += scrollIntoView(r.Y, r.H, b.Y, b.H)
+	sp.SetScrollOffset(x, y)
+}
+
+// scrollIntoView returns how far to scroll so [pos, pos+size) is inside [view, view+viewSize).
+// Items larger than the view are aligned to its start.
+func scrollIntoView(pos, size, view, viewSize float64) float64 {
+	switch {
+	case pos < view || size > viewSize:
+		return pos - view
+	case pos+size > view+viewSize:
+		return pos + size - (view + viewSize)
+	default:
+		return 0
+	}
+}
+
+// CanScrollVertically reports whether the content is taller than the panel.
+func (sp *ScrollPanel) CanScrollVertically() bool {
+	return sp.c.ContentH > sp.c.Bounds.H
+}
+
+// CanScrollHorizontally reports whether the content is wider than the panel.
+func (sp *ScrollPanel) CanScrollHorizontally() bool {
+	return sp.c.ContentW > sp.c.Bounds.W
+}
+
+// HandleWheel scrolls by wheel offsets as reported by ebiten.Wheel (positive = up/left).
+// Returns true when the panel could scroll in the requested direction.
+func (sp *ScrollPanel) HandleWheel(dx, dy float64) bool {
+	consumed := false
+	if dy != 0 && sp.CanScrollVertically() {
+		sp.ScrollBy(0, -dy*sp.ScrollStep)
+		consumed = true
+	}
+	if dx != 0 && sp.CanScrollHorizontally() {
+		sp.ScrollBy(-dx*sp.ScrollStep, 0)
+		consumed = true
+	}
+	return consumed
+}
+
+// verticalTrack returns the vertical scrollbar track, or an empty rect when not shown.
+func (sp *ScrollPanel) verticalTrack() layout.Rect {
+	if !sp.CanScrollVertically() {
+		return layout.Rect{}
+	}
+	b := sp.Bounds()
+	size := sp.metrics().ScrollbarThickness
+	h := b.H
+	if sp.CanScrollHorizontally() {
+		h -= size
+	}
+	return layout.Rect{X: b.X + b.W - size, Y: b.Y, W: size, H: h}
+}
+
+// horizontalTrack returns the horizontal scrollbar track, or an empty rect when not shown.
+func (sp *ScrollPanel) horizontalTrack() layout.Rect {
+	if !sp.CanScrollHorizontally() {
+		return layout.Rect{}
+	}
+	b := sp.Bounds()
+	size := sp.metrics().ScrollbarThickness
+	w := b.W
+	if sp.CanScrollVertically() {
+		w -= size
+	}
+	return layout.Rect{X: b.X, Y: b.Y + b.H - size, W: w, H: size}
+}
+
+// metrics returns the scrollbar sizes of the UI theme.
+func (sp *ScrollPanel) metrics() ThemeMetrics {
+	if sp.ui == nil {
+		return DefaultThemeMetrics()
+	}
+	return sp.ui.theme.Metrics
+}
+
+// thumbSpan returns the thumb start and length along a track; the thumb is at least
+// minLen long unless the track is shorter.
+func thumbSpan(trackPos, trackLen, view, content, offset, minLen float64) (pos, length float64) {
+	length = max(trackLen*view/content, min(minLen, trackLen))
+	travel := trackLen - length
+	maxOffset := content - view
+	if maxOffset <= 0 || travel <= 0 {
+		return trackPos, length
+	}
+	return trackPos + travel*offset/maxOffset, length
+}
+
+func (sp *ScrollPanel) verticalThumb() layout.Rect {
+	t := sp.verticalTrack()
+	if t.Empty() {
+		return t
+	}
+	y, h := thumbSpan(t.Y, t.H, sp.c.Bounds.H, sp.c.ContentH, sp.c.ScrollY, sp.metrics().ScrollThumbMin)
+	return layout.Rect{X: t.X, Y: y, W: t.W, H: h}
+}
+
+func (sp *ScrollPanel) horizontalThumb() layout.Rect {
+	t := sp.horizontalTrack()
+	if t.Empty() {
+		return t
+	}
+	x, w := thumbSpan(t.X, t.W, sp.c.Bounds.W, sp.c.ContentW, sp.c.ScrollX, sp.metrics().ScrollThumbMin)
+	return layout.Rect{X: x, Y: t.Y, W: w, H: t.H}
+}
+
+// hitScrollbar returns which scrollbar track contains the point.
+func (sp *ScrollPanel) hitScrollbar(x, y float64) scrollAxis {
+	if !rendering.PointWithinBounds(x, y, sp.c.Clip) {
+		return scrollNone
+	}
+	if rendering.PointWithinBounds(x, y, sp.verticalTrack()) {
+		return scrollVertical
+	}
+	if rendering.PointWithinBounds(x, y, sp.horizontalTrack()) {
+		return scrollHorizontal
+	}
+	return scrollNone
+}
+
+// HitTestScrollbar reports whether the point is on one of the scrollbars.
+func (sp *ScrollPanel) HitTestScrollbar(x, y float64) bool {
+	return sp.hitScrollbar(x, y) != scrollNone
+}
+
+// SetHoverPoint updates scrollbar hover highlighting for the cursor position.
+func (sp *ScrollPanel) SetHoverPoint(x, y float64) {
+	sp.hoverAxis = sp.hitScrollbar(x, y)
+}
+
+// StartDrag begins dragging a scrollbar thumb at the point. Pressing the track outside the
+// thumb first centers the thumb on the cursor. Returns false if the point is not on a scrollbar.
+func (sp *ScrollPanel) StartDrag(x, y float64) bool {
+	axis := sp.hitScrollbar(x, y)
+	switch axis {
+	case scrollVertical:
+		thumb := sp.verticalThumb()
+		if !rendering.PointWithinBounds(x, y, thumb) {
+			sp.dragOffset = thumb.H / 2
+		} else {
+			sp.dragOffset = y - thumb.Y
+		}
+	case scrollHorizontal:
+		thumb := sp.horizontalThumb()
+		if !rendering.PointWithinBounds(x, y, thumb) {
+			sp.dragOffset = thumb.W / 2
+		} else {
+			sp.dragOffset = x - thumb.X
+		}
+	default:
+		return false
+	}
+	sp.dragAxis = axis
+	sp.UpdateDrag(x, y)
+	return true
+}
+
+// UpdateDrag moves the dragged thumb so it follows the cursor.
+func (sp *ScrollPanel) UpdateDrag(x, y float64) {
+	switch sp.dragAxis {
+	case scrollVertical:
+		t, thumb := sp.verticalTrack(), sp.verticalThumb()
+		sp.c.ScrollY = dragOffsetFor(y-sp.dragOffset, t.Y, t.H, thumb.H, sp.c.ContentH-sp.c.Bounds.H)
+	case scrollHorizontal:
+		t, thumb := sp.horizontalTrack(), sp.horizontalThumb()
+		sp.c.ScrollX = dragOffsetFor(x-sp.dragOffset, t.X, t.W, thumb.W, sp.c.ContentW-sp.c.Bounds.W)
+	}
+}
+
+// dragOffsetFor maps a thumb start position back to a scroll offset.
+func dragOffsetFor(thumbPos, trackPos, trackLen, thumbLen, maxOffset float64) float64 {
+	travel := trackLen - thumbLen
+	if travel <= 0 || maxOffset <= 0 {
+		return 0
+	}
+	ratio := (thumbPos - trackPos) / travel
+	return max(0, min(ratio, 1)) * maxOffset
+}
+
+// StopDrag ends a thumb drag.
+func (sp *ScrollPanel) StopDrag() {
+	sp.dragAxis = scrollNone
+}
+
+// IsDragging returns whether a scrollbar thumb is being dragged.
+func (sp *ScrollPanel) IsDragging() bool {
+	return sp.dragAxis != scrollNone
+}
+
+// ScrollTheme controls scrollbar drawing colors.
+type ScrollTheme struct {
+	Track      colors.Color
+	Thumb      colors.Color
+	ThumbHover colors.Color
+	ThumbDrag  colors.Color
+}
+
+// DefaultScrollTheme returns the default scrollbar theme.
+func DefaultScrollTheme() ScrollTheme {
+	return ScrollTheme{
+		Track:      colors.RGBA(0, 0, 0, 60),
+		Thumb:      colors.HexOr("#5a5a5a", colors.RGB(90, 90, 90)),
+		ThumbHover: colors.HexOr("#707070", colors.RGB(112, 112, 112)),
+		ThumbDrag:  colors.HexOr("#4a9eff", colors.RGB(74, 158, 255)),
+	}
+}
+
+// DrawForeground draws the scrollbars over the panel content.
+func (sp *ScrollPanel) DrawForeground(ctx *DrawContext) {
+	sp.DrawScrollbars(ctx.Dst, themeOr(sp.ScrollTheme, ctx.Theme.Scroll))
+}
+
+// HitTestForeground reports whether the point is on one of the scrollbars, which take
+// clicks before the panel's children.
+func (sp *ScrollPanel) HitTestForeground(x, y float64) bool {
+	return sp.HitTestScrollbar(x, y)
+}
+
+// HandleEvent scrolls on wheel events, highlights hovered scrollbars and drags their thumbs.
+func (sp *ScrollPanel) HandleEvent(e *Event) {
+	switch e.Type {
+	case EventMouseMove:
+		sp.SetHoverPoint(e.X, e.Y)
+		if sp.IsDragging() {
+			sp.UpdateDrag(e.X, e.Y)
+		}
+	case EventMouseLeave:
+		sp.hoverAxis = scrollNone
+	case EventMouseDown:
+		if e.isPrimaryPress() && sp.StartDrag(e.X, e.Y) {
+			if sp.ui != nil {
+				sp.ui.SetPointerCapture(sp)
+			}
+			e.StopPropagation()
+		}
+	case EventMouseUp:
+		sp.StopDrag()
+	case EventWheel:
+		if sp.HandleWheel(e.WheelX, e.WheelY) {
+			e.StopPropagation()
+		}
+	}
+}
+
+// DrawScrollbars draws the scrollbars over the panel content. Draw it after the children.
+func (sp *ScrollPanel) DrawScrollbars(dst *ebiten.Image, theme ScrollTheme) {
+	sp.drawScrollbar(dst, theme, scrollVertical, sp.verticalTrack(), sp.verticalThumb())
+	sp.drawScrollbar(dst, theme, scrollHorizontal, sp.horizontalTrack(), sp.horizontalThumb())
+}
+
+func (sp *ScrollPanel) drawScrollbar(dst *ebiten.Image, theme ScrollTheme, axis scrollAxis, track, thumb layout.Rect) {
+	if track.Empty() {
+		return
+	}
+	rendering.FillRect(dst, track.X, track.Y, track.W, track.H, theme.Track)
+	fill := theme.Thumb
+	if sp.dragAxis == axis {
+		fill = theme.ThumbDrag
+	} else if sp.hoverAxis == axis {
+		fill = theme.ThumbHover
+	}
+	rendering.FillRect(dst, thumb.X+2, thumb.Y+2, thumb.W-4, thumb.H-4, fill)
+}
@@ -0,0 +1,247 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Shortcut is a key pressed with an exact set of modifiers, e.g. Ctrl+S.
+type Shortcut struct {
+	Key  ebiten.Key
+	Mods Modifiers
+}
+
+// modifierNames lists the modifiers in the order String writes them.
+var modifierNames = []struct {
+	mod  Modifiers
+	name string
+}{
+	{ModCtrl, "Ctrl"},
+	{ModAlt, "Alt"},
+	{ModShift, "Shift"},
+	{ModMeta, "Meta"},
+}
+
+// modifierAliases maps the lower-case modifier names ParseShortcut accepts.
+var modifierAliases = map[string]Modifiers{
+	"ctrl":    ModCtrl,
+	"control": ModCtrl,
+	"alt":     ModAlt,
+	"option":  ModAlt,
+	"shift":   ModShift,
+	"meta":    ModMeta,
+	"cmd":     ModMeta,
+	"command": ModMeta,
+	"super":   ModMeta,
+}
+
+// keyLabels are the names String uses instead of ebiten's for some keys.
+var keyLabels = map[ebiten.Key]string{
+	ebiten.KeyDigit0:       "0",
+	ebiten.KeyDigit1:       "1",
+	ebiten.KeyDigit2:       "2",
+	ebiten.KeyDigit3:       "3",
+	ebiten.KeyDigit4:       "4",
+	ebiten.KeyDigit5:       "5",
+	ebiten.KeyDigit6:       "6",
+	ebiten.KeyDigit7:       "7",
+	ebiten.KeyDigit8:       "8",
+	ebiten.KeyDigit9:       "9",
+	ebiten.KeyMinus:        "-",
+	ebiten.KeyEqual:        "=",
+	ebiten.KeyComma:        ",",
+	ebiten.KeyPeriod:       ".",
+	ebiten.KeySlash:        "/",
+	ebiten.KeyBackslash:    "\\",
+	ebiten.KeySemicolon:    ";",
+	ebiten.KeyQuote:        "'",
+	ebiten.KeyBracketLeft:  "[",
+	ebiten.KeyBracketRight: "]",
+	ebiten.KeyBackquote:    "`",
+	ebiten.KeyArrowUp:      "Up",
+	ebiten.KeyArrowDown:    "Down",
+	ebiten.KeyArrowLeft:    "Left",
+	ebiten.KeyArrowRight:   "Right",
+	ebiten.KeyPageUp:       "PgUp",
+	ebiten.KeyPageDown:     "PgDn",
+	ebiten.KeyDelete:       "Del",
+	ebiten.KeyInsert:       "Ins",
+	ebiten.KeyEscape:       "Esc",
+}
+
+// ParseShortcut parses text such as "Ctrl+S", "Ctrl+Shift+Z" or "F5". Parts are separated
+// by "+" and case does not matter. Modifiers are Ctrl (Control), Alt (Option), Shift and
+// Meta (Cmd, Command, Super); the key is an ebiten key name ("A", "F5", "Enter",
+// "ArrowUp", ...) or one of the labels String writes ("1", "=", "Up", "Del", ...).
+func ParseShortcut(s string) (Shortcut, error) {
+	parts := strings.Split(s, "+")
+	var sc Shortcut
+	for _, p := range parts[:len(parts)-1] {
+		mod, ok := modifierAliases[strings.ToLower(strings.TrimSpace(p))]
+		if !ok {
+			return Shortcut{}, fmt.Errorf("shortcut %q: unknown modifier %q", s, p)
+		}
+		sc.Mods |= mod
+	}
+	name := strings.TrimSpace(parts[len(parts)-1])
+	if err := sc.Key.UnmarshalText([]byte(name)); err == nil {
+		return sc, nil
+	}
+	for k, label := range keyLabels {
+		if strings.EqualFold(label, name) {
+			sc.Key = k
+			return sc, nil
+		}
+	}
+	return Shortcut{}, fmt.Errorf("shortcut %q: unknown key %q", s, name)
+}
+
+// String formats the shortcut as ParseShortcut reads it, e.g. "Ctrl+Shift+Z".
+func (s Shortcut) String() string {
+	var b strings.Builder
+	for _, m := range modifierNames {
+		if s.Mods.Has(m.mod) {
+			b.WriteString(m.name + "+")
+		}
+	}
+	if label, ok := keyLabels[s.Key]; ok {
+		b.WriteString(label)
+	} else {
+		b.WriteString(s.Key.String())
+	}
+	return b.String()
+}
+
+// AddShortcut runs fn when s is pressed and the widget with focus does not handle the key.
+// It replaces an earlier global binding of s. It is a shorthand for registering a command
+// named after s and binding it globally (see Accelerators), and returns a *ConflictError,
+// binding nothing, when s starts a longer global accelerator.
+func (u *UI) AddShortcut(s Shortcut, fn func()) error {
+	name := shortcutCommand(s)
+	keys := Accelerator{s}
+	bindings := slices.Clone(u.accel.bindings)
+	u.accel.unbind(keys, nil, false)
+	if err := u.accel.bind(Binding{Keys: keys, Command: name}); err != nil {
+		u.accel.bindings = bindings
+		u.accel.generation++
+		return err
+	}
+	u.accel.commands[name] = Command{Name: name, Run: fn}
+	return nil
+}
+
+// RemoveShortcut removes the global binding of s, added with AddShortcut or
+// Accelerators.Bind, and the command AddShortcut registered for s.
+func (u *UI) RemoveShortcut(s Shortcut) {
+	u.accel.unbind(Accelerator{s}, nil, false)
+	delete(u.accel.commands, shortcutCommand(s))
+}
+
+// shortcutCommand returns the name of the command AddShortcut registers for s.
+func shortcutCommand(s Shortcut) string {
+	return "shortcut " + s.String()
+}
+
+// menuShortcuts keeps the entry shortcuts of a menu bound in the accelerators of its UI.
+// The menu syncs them before each layout; the bindings are rebuilt only when the shortcuts,
+// their entries, the UI or the scope change, and each shortcut text is parsed once.
+// Shortcuts left out because of a conflict are bound again once the bindings change.
+type menuShortcuts struct {
+	parsed     map[string]parsedShortcut
+	bound      []menuShortcut
+	ui         *UI
+	scope      Widget
+	generation int // Accelerators.generation when the left out shortcuts were last tried
+}
+
+type parsedShortcut struct {
+	keys Shortcut
+	ok   bool
+}
+
+// menuShortcut is an entry shortcut; command is empty while it conflicts with another
+// binding and is left out.
+type menuShortcut struct {
+	keys    Shortcut
+	path    []int
+	command string
+}
+
+// menuShortcutWalk calls visit with the keys and path of every entry of a menu that has a
+// shortcut. path is only valid during the call.
+type menuShortcutWalk func(visit func(keys Shortcut, path []int))
+
+// parse returns the shortcut a Shortcut field holds, parsing each text once.
+func (ms *menuShortcuts) parse(text string) (Shortcut, bool) {
+	p, ok := ms.parsed[text]
+	if !ok {
+		keys, err := ParseShortcut(text)
+		p = parsedShortcut{keys: keys, ok: err == nil}
+		if ms.parsed == nil {
+			ms.parsed = make(map[string]parsedShortcut)
+		}
+		ms.parsed[text] = p
+	}
+	return p.keys, p.ok
+}
+
+// sync binds the shortcuts walk visits in the accelerators of u, only while focus is inside
+// scope unless scope is nil. run runs the entry at a path and enabled reports whether it
+// can run now. A nil u removes the bindings.
+func (ms *menuShortcuts) sync(u *UI, scope Widget, walk menuShortcutWalk, run func(path []int), enabled func(path []int) bool) {
+	if u != ms.ui || scope != ms.scope || !ms.unchanged(walk) {
+		ms.clear()
+		ms.ui, ms.scope = u, scope
+		if u == nil {
+			return
+		}
+		walk(func(keys Shortcut, path []int) {
+			ms.bound = append(ms.bound, menuShortcut{keys: keys, path: slices.Clone(path)})
+		})
+	} else if u == nil || ms.generation == u.accel.generation {
+		return
+	}
+	for i := range ms.bound {
+		b := &ms.bound[i]
+		if b.command != "" {
+			continue
+		}
+		name := fmt.Sprintf("menu %p %v", ms, b.path)
+		if u.accel.bind(Binding{Keys: Accelerator{b.keys}, Command: name, Scope: scope}) != nil {
+			continue
+		}
+		path := b.path
+		u.accel.commands[name] = Command{
+			Name:    name,
+			Run:     func() { run(path) },
+			Enabled: func() bool { return enabled(path) },
+		}
+		b.command = name
+	}
+	ms.generation = u.accel.generation
+}
+
+// unchanged reports whether walk visits the shortcuts that are bound, in the same order.
+func (ms *menuShortcuts) unchanged(walk menuShortcutWalk) bool {
+	i, same := 0, true
+	walk(func(keys Shortcut, path []int) {
+		if same && (i >= len(ms.bound) || ms.bound[i].keys != keys || !slices.Equal(ms.bound[i].path, path)) {
+			same = false
+		}
+		i++
+	})
+	return same && i == len(ms.bound)
+}
+
+// clear removes the bindings and their commands.
+func (ms *menuShortcuts) clear() {
+	for _, b := range ms.bound {
+		if b.command != "" {
+			ms.ui.accel.removeCommand(b.command)
+		}
+	}
+	ms.bound = ms.bound[:0]
+}
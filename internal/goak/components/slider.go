@@ -5,6 +5,7 @@ import (
 	"goak/internal/goak/colors"
 	"goak/internal/goak/layout"
 	"goak/internal/goak/rendering"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
@@ -12,8 +13,10 @@ import (
 
 // Slider is a horizontal slider control for selecting values in a range.
 type Slider struct {
-	c          *layout.Container
+	Element
 	Label      string
+	Font       *Font        // nil uses the UI default font
+	Theme      *SliderTheme // nil uses the UI theme
 	Min        float64
 	Max        float64
 	Value      float64
@@ -25,8 +28,7 @@ type Slider struct {
 
 // NewSlider creates a standalone slider. Add it with panel.AddSlider(slider).
 func NewSlider(width, height layout.Size, label string, min, max, initial float64) *Slider {
-	return &Slider{
-		c:         layout.NewContainer(width, height),
+	s := &Slider{
 		Label:     label,
 		Min:       min,
 		Max:       max,
@@ -34,14 +36,11 @@ func NewSlider(width, height layout.Size, label string, min, max, initial float6
 		Step:      (max - min) / 100.0,
 		showValue: true,
 	}
+	s.Init(layout.NewContainer(width, height))
+	s.SetFocusable(true)
+	return s
 }
 
-// Bounds returns the computed layout rect after Layout.
-func (s *Slider) Bounds() layout.Rect { return s.c.Bounds }
-
-// Container returns the layout node for this slider (internal use).
-func (s *Slider) Container() *layout.Container { return s.c }
-
 // SetStep sets the increment step for the slider.
 func (s *Slider) SetStep(step float64) {
 	s.Step = step
@@ -74,7 +73,12 @@ func DefaultSliderTheme() SliderTheme {
 	}
 }
 
-func (s *Slider) Draw(dst *ebiten.Image, face text.GoTextFace, theme SliderTheme) {
+// Draw draws the label, track, thumb and value.
+func (s *Slider) Draw(ctx *DrawContext) {
+	s.draw(ctx.Dst, ctx.FaceFor(s.Font), themeOr(s.Theme, ctx.Theme.Slider))
+}
+
+func (s *Slider) draw(dst *ebiten.Image, face text.Face, theme SliderTheme) {
 	bound := s.Bounds()
 
 	// Calculate dimensions
@@ -138,15 +142,20 @@ func (s *Slider) UpdateValue(mouseX float64) {
 		normalizedX = 1
 	}
 
-	newValue := s.Min + normalizedX*(s.Max-s.Min)
+	s.SetValue(s.Min + normalizedX*(s.Max-s.Min))
+}
 
+// SetValue sets the value, rounded to Step and kept within Min and Max, and calls
+// OnChanged if it changed.
+func (s *Slider) SetValue(v float64) {
 	// Apply step
 	if s.Step > 0 {
-		newValue = float64(int(newValue/s.Step+0.5)) * s.Step
+		v = math.Round(v/s.Step) * s.Step
 	}
+	v = max(s.Min, min(v, s.Max))
 
-	if newValue != s.Value {
-		s.Value = newValue
+	if v != s.Value {
+		s.Value = v
 		if s.OnChanged != nil {
 			s.OnChanged(s.Value)
 		}
@@ -167,3 +176,48 @@ func (s *Slider) StopDrag() {
 func (s *Slider) IsDragging() bool {
 	return s.isDragging
 }
+
+// HandleEvent drags the thumb: pressing jumps to the cursor and captures the pointer
+// until the button is released. While focused, the arrow keys move by Step, Page Up/Down
+// by ten steps and Home/End jump to Min/Max.
+func (s *Slider) HandleEvent(e *Event) {
+	switch e.Type {
+	case EventMouseDown:
+		if !e.isPrimaryPress() {
+			return
+		}
+		s.StartDrag()
+		s.UpdateValue(e.X)
+		if s.ui != nil {
+			s.ui.SetPointerCapture(s)
+		}
+	case EventMouseMove:
+		if s.isDragging {
+			s.UpdateValue(e.X)
+		}
+	case EventMouseUp:
+		s.StopDrag()
+	case EventKeyDown:
+		step := s.Step
+		if step <= 0 {
+			step = (s.Max - s.Min) / 100
+		}
+		switch e.Key {
+		case ebiten.KeyArrowLeft, ebiten.KeyArrowDown:
+			s.SetValue(s.Value - step)
+		case ebiten.KeyArrowRight, ebiten.KeyArrowUp:
+			s.SetValue(s.Value + step)
+		case ebiten.KeyPageDown:
+			s.SetValue(s.Value - step*10)
+		case ebiten.KeyPageUp:
+			s.SetValue(s.Value + step*10)
+		case ebiten.KeyHome:
+			s.SetValue(s.Min)
+		case ebiten.KeyEnd:
+			s.SetValue(s.Max)
+		default:
+			return
+		}
+		e.StopPropagation()
+	}
+}
@@ -0,0 +1,724 @@
+package components
+
+import (
+	"goak/internal/goak/colors"
+	"goak/internal/goak/layout"
+	"goak/internal/goak/rendering"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// TextPos is a position in a TextArea: a line index and a character offset in that line.
+type TextPos struct {
+	Line, Col int
+}
+
+func (p TextPos) before(q TextPos) bool {
+	return p.Line < q.Line || p.Line == q.Line && p.Col < q.Col
+}
+
+// textLine is one line of a TextArea with its cached soft-wrap breaks.
+type textLine struct {
+	text   []rune
+	gen    int   // wrap generation the breaks were computed for; 0 means not wrapped yet
+	breaks []int // offset where each wrapped row after the first starts
+}
+
+// rowRef identifies a visual row: one row of a line after soft wrapping.
+type rowRef struct {
+	line, row int
+}
+
+func (r rowRef) before(q rowRef) bool {
+	return r.line < q.line || r.line == q.line && r.row < q.row
+}
+
+// TextArea is a multi-line text editor with soft word wrap and vertical scrolling.
+// Lines are wrapped lazily and only visible rows are shaped, so long documents stay cheap.
+// Keys work as in TextInput, plus Up/Down, Page Up/Down, Ctrl+Home/End and Enter for a
+// new line. Create with NewTextArea; add with panel.AddTextArea(area).
+type TextArea struct {
+	Element
+	Font      *Font          // nil uses the UI default font
+	Theme     *TextAreaTheme // nil uses the UI theme
+	OnChanged func()         // called after every edit; read the contents with Text
+
+	lines     []textLine
+	caret     TextPos
+	anchor    TextPos // other end of the selection; equals caret when nothing is selected
+	goalX     float64 // x kept while moving up and down; negative when unset
+	top       rowRef  // first visible row
+	reveal    bool    // scroll the caret into view on the next draw
+	focused   bool
+	selecting bool // mouse button held after pressing in the area
+
+	wrapGen  int // bumped when the wrap width or face changes, invalidating every line
+	wrapW    float64
+	wrapFace text.Face
+}
+
+const (
+	textAreaPadding   = 6.0
+	textAreaScrollbar = 6.0
+	textAreaMinWidth  = 240.0
+	textAreaMinLines  = 4
+	textAreaWheelRows = 3
+)
+
+// NewTextArea creates a standalone, empty text area. Add it with panel.AddTextArea(area).
+func NewTextArea(width, height layout.Size) *TextArea {
+	ta := &TextArea{lines: []textLine{{}}, goalX: -1, wrapGen: 1}
+	ta.Init(layout.NewContainer(width, height))
+	ta.SetFocusable(true)
+	ta.c.Measure = ta.measure
+	return ta
+}
+
+// measure reports room for textAreaMinLines lines, textAreaMinWidth wide, plus padding.
+func (ta *TextArea) measure(float64) (float64, float64) {
+	_, th := measureText(ta.ui, ta.Font, "M")
+	return textAreaMinWidth + textAreaPadding*2, th*textAreaMinLines + textAreaPadding*2
+}
+
+// Text returns the whole text, lines joined with "\n".
+func (ta *TextArea) Text() string {
+	var sb strings.Builder
+	for i, l := range ta.lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(string(l.text))
+	}
+	return sb.String()
+}
+
+// SetText replaces the text and moves the caret to the start. It does not call OnChanged.
+func (ta *TextArea) SetText(s string) {
+	parts := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	ta.lines = make([]textLine, len(parts))
+	for i, p := range parts {
+		ta.lines[i].text = []rune(p)
+	}
+	ta.top = rowRef{}
+	ta.moveCaret(TextPos{}, false)
+}
+
+// LineCount returns the number of lines (not counting soft wraps).
+func (ta *TextArea) LineCount() int {
+	return len(ta.lines)
+}
+
+// Line returns line i without its line break.
+func (ta *TextArea) Line(i int) string {
+	return string(ta.lines[i].text)
+}
+
+// Caret returns the caret position.
+func (ta *TextArea) Caret() TextPos {
+	return ta.caret
+}
+
+// SetCaret moves the caret to p (clamped to the text), clears the selection and scrolls
+// the caret into view.
+func (ta *TextArea) SetCaret(p TextPos) {
+	ta.moveCaret(p, false)
+}
+
+// SelectAll selects the whole text.
+func (ta *TextArea) SelectAll() {
+	ta.anchor = TextPos{}
+	last := len(ta.lines) - 1
+	ta.caret = TextPos{Line: last, Col: len(ta.lines[last].text)}
+}
+
+// Selection returns the selected range in text order; start == end when nothing is
+// selected, in which case both are the caret position.
+func (ta *TextArea) Selection() (start, end TextPos) {
+	if ta.anchor.before(ta.caret) {
+		return ta.anchor, ta.caret
+	}
+	return ta.caret, ta.anchor
+}
+
+// SelectedText returns the selected text, lines joined with "\n".
+func (ta *TextArea) SelectedText() string {
+	start, end := ta.Selection()
+	if start.Line == end.Line {
+		return string(ta.lines[start.Line].text[start.Col:end.Col])
+	}
+	var sb strings.Builder
+	sb.WriteString(string(ta.lines[start.Line].text[start.Col:]))
+	for i := start.Line + 1; i < end.Line; i++ {
+		sb.WriteByte('\n')
+		sb.WriteString(string(ta.lines[i].text))
+	}
+	sb.WriteByte('\n')
+	sb.WriteString(string(ta.lines[end.Line].text[:end.Col]))
+	return sb.String()
+}
+
+// clamp returns p moved inside the text.
+func (ta *TextArea) clamp(p TextPos) TextPos {
+	p.Line = max(0, min(p.Line, len(ta.lines)-1))
+	p.Col = max(0, min(p.Col, len(ta.lines[p.Line].text)))
+	return p
+}
+
+// moveCaret moves the caret to p; with extend the selection anchor stays put.
+func (ta *TextArea) moveCaret(p TextPos, extend bool) {
+	ta.caret = ta.clamp(p)
+	if !extend {
+		ta.anchor = ta.caret
+	}
+	ta.goalX = -1
+	ta.reveal = true
+}
+
+// insert replaces the selection with s, which may contain line breaks.
+func (ta *TextArea) insert(s string) {
+	s = strings.Map(func(r rune) rune {
+		if r != '\n' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	start, end := ta.Selection()
+	if s == "" && start == end {
+		return
+	}
+	ta.deleteSpan(start, end)
+
+	parts := strings.Split(s, "\n")
+	line := ta.lines[start.Line].text
+	tail := slices.Clone(line[start.Col:])
+	first := []rune(parts[0])
+	caret := TextPos{Line: start.Line, Col: start.Col + len(first)}
+	if len(parts) == 1 {
+		ta.lines[start.Line].text = slices.Concat(line[:start.Col], first, tail)
+	} else {
+		ta.lines[start.Line].text = slices.Concat(line[:start.Col], first)
+		added := make([]textLine, len(parts)-1)
+		for i, p := range parts[1:] {
+			added[i].text = []rune(p)
+		}
+		last := &added[len(added)-1]
+		caret = TextPos{Line: start.Line + len(added), Col: len(last.text)}
+		last.text = append(last.text, tail...)
+		ta.lines = slices.Insert(ta.lines, start.Line+1, added...)
+	}
+	ta.lines[start.Line].gen = 0
+	ta.moveCaret(caret, false)
+	ta.changed()
+}
+
+// deleteSpan removes the text between start and end without notifying OnChanged and
+// reports whether anything was removed.
+func (ta *TextArea) deleteSpan(start, end TextPos) bool {
+	if !start.before(end) {
+		return false
+	}
+	first := ta.lines[start.Line].text
+	last := ta.lines[end.Line].text
+	ta.lines[start.Line].text = slices.Concat(first[:start.Col], last[end.Col:])
+	ta.lines[start.Line].gen = 0
+	ta.lines = slices.Delete(ta.lines, start.Line+1, end.Line+1)
+	ta.caret, ta.anchor = start, start
+	return true
+}
+
+// deleteRange removes the text between start and end and leaves the caret at start.
+func (ta *TextArea) deleteRange(start, end TextPos) {
+	start, end = ta.clamp(start), ta.clamp(end)
+	if ta.deleteSpan(start, end) {
+		ta.moveCaret(start, false)
+		ta.changed()
+	}
+}
+
+func (ta *TextArea) changed() {
+	if ta.OnChanged != nil {
+		ta.OnChanged()
+	}
+}
+
+// left and right step one character, across line breaks.
+func (ta *TextArea) left(p TextPos) TextPos {
+	if p.Col > 0 {
+		return TextPos{Line: p.Line, Col: p.Col - 1}
+	}
+	if p.Line > 0 {
+		return TextPos{Line: p.Line - 1, Col: len(ta.lines[p.Line-1].text)}
+	}
+	return p
+}
+
+func (ta *TextArea) right(p TextPos) TextPos {
+	if p.Col < len(ta.lines[p.Line].text) {
+		return TextPos{Line: p.Line, Col: p.Col + 1}
+	}
+	if p.Line+1 < len(ta.lines) {
+		return TextPos{Line: p.Line + 1}
+	}
+	return p
+}
+
+// wordLeft and wordRight step one word; at a line edge they cross the line break.
+func (ta *TextArea) wordLeft(p TextPos) TextPos {
+	if p.Col == 0 {
+		return ta.left(p)
+	}
+	return TextPos{Line: p.Line, Col: wordStart(ta.lines[p.Line].text, p.Col)}
+}
+
+func (ta *TextArea) wordRight(p TextPos) TextPos {
+	if p.Col == len(ta.lines[p.Line].text) {
+		return ta.right(p)
+	}
+	return TextPos{Line: p.Line, Col: wordEnd(ta.lines[p.Line].text, p.Col)}
+}
+
+// syncWrap starts a new wrap generation when the wrap width or face changed.
+func (ta *TextArea) syncWrap(face text.Face, width float64) {
+	if width != ta.wrapW || face != ta.wrapFace {
+		ta.wrapW = width
+		ta.wrapFace = face
+		ta.wrapGen++
+	}
+}
+
+// breaks returns the soft-wrap breaks of line i, wrapping it first if it changed.
+func (ta *TextArea) breaks(i int) []int {
+	l := &ta.lines[i]
+	if l.gen != ta.wrapGen {
+		l.breaks = nil
+		if ta.wrapFace != nil {
+			l.breaks = wrapRunes(l.text, ta.wrapFace, ta.wrapW)
+		}
+		l.gen = ta.wrapGen
+	}
+	return l.breaks
+}
+
+// wrapRunes breaks line into rows no wider than width, between words where possible,
+// and returns the offset where each row after the first starts. Spaces may hang past
+// the edge; a word wider than a whole row is broken between characters.
+func wrapRunes(line []rune, face text.Face, width float64) []int {
+	if width <= 0 {
+		return nil
+	}
+	var breaks []int
+	rowStart, x := 0, 0.0
+	for i := 0; i < len(line); {
+		wordEnd := i
+		for wordEnd < len(line) && !unicode.IsSpace(line[wordEnd]) {
+			wordEnd++
+		}
+		end := wordEnd
+		for end < len(line) && unicode.IsSpace(line[end]) {
+			end++
+		}
+		wordW := text.Advance(string(line[i:wordEnd]), face)
+		if x+wordW > width && i > rowStart {
+			breaks = append(breaks, i)
+			rowStart, x = i, 0
+		}
+		for wordW > width && wordEnd-i > 1 {
+			i += fitRunes(line[i:wordEnd], face, width)
+			breaks = append(breaks, i)
+			rowStart = i
+			wordW = text.Advance(string(line[i:wordEnd]), face)
+		}
+		x += wordW + text.Advance(string(line[wordEnd:end]), face)
+		i = end
+	}
+	return breaks
+}
+
+// fitRunes returns how many leading runes fit in width, at least one.
+func fitRunes(runes []rune, face text.Face, width float64) int {
+	n := sort.Search(len(runes)+1, func(i int) bool {
+		return advanceTo(runes, i, face) > width
+	})
+	return max(n-1, 1)
+}
+
+func (ta *TextArea) rowCount(line int) int {
+	return len(ta.breaks(line)) + 1
+}
+
+// rowRange returns the part of its line that row r shows.
+func (ta *TextArea) rowRange(r rowRef) (start, end int) {
+	br := ta.breaks(r.line)
+	if r.row > 0 {
+		start = br[r.row-1]
+	}
+	end = len(ta.lines[r.line].text)
+	if r.row < len(br) {
+		end = br[r.row]
+	}
+	return start, end
+}
+
+// rowOf returns the row showing position p. A position at a break starts the next row.
+func (ta *TextArea) rowOf(p TextPos) rowRef {
+	br := ta.breaks(p.Line)
+	return rowRef{line: p.Line, row: sort.Search(len(br), func(i int) bool { return br[i] > p.Col })}
+}
+
+// stepRows moves n rows down (up for negative n), stopping at the ends of the text.
+func (ta *TextArea) stepRows(r rowRef, n int) rowRef {
+	for ; n > 0; n-- {
+		switch {
+		case r.row+1 < ta.rowCount(r.line):
+			r.row++
+		case r.line+1 < len(ta.lines):
+			r = rowRef{line: r.line + 1}
+		default:
+			return r
+		}
+	}
+	for ; n < 0; n++ {
+		switch {
+		case r.row > 0:
+			r.row--
+		case r.line > 0:
+			r = rowRef{line: r.line - 1, row: ta.rowCount(r.line-1) - 1}
+		default:
+			return r
+		}
+	}
+	return r
+}
+
+// rowX returns the x offset of col within row r.
+func (ta *TextArea) rowX(r rowRef, col int) float64 {
+	start, _ := ta.rowRange(r)
+	return advanceTo(ta.lines[r.line].text[start:], col-start, ta.wrapFace)
+}
+
+// colAt returns the column in row r closest to x. On a wrapped row it stays before the
+// break, which would otherwise put the caret on the next row.
+func (ta *TextArea) colAt(r rowRef, x float64) int {
+	start, end := ta.rowRange(r)
+	if r.row < ta.rowCount(r.line)-1 && end > start {
+		end--
+	}
+	return start + nearestIndex(ta.lines[r.line].text[start:end], x, ta.wrapFace)
+}
+
+// view returns the text area's text rect, row height and number of fully visible rows,
+// and prepares wrapping for that width.
+func (ta *TextArea) view(face text.Face) (inner layout.Rect, lineH float64, rows int) {
+	b := ta.Bounds()
+	inner = layout.Rect{
+		X: b.X + textAreaPadding,
+		Y: b.Y + textAreaPadding,
+		W: b.W - textAreaPadding*2 - textAreaScrollbar,
+		H: b.H - textAreaPadding*2,
+	}
+	lineH = lineHeight(face)
+	rows = 1
+	if lineH > 0 {
+		rows = max(int(inner.H/lineH), 1)
+	}
+	ta.syncWrap(face, inner.W)
+	return inner, lineH, rows
+}
+
+// clampTop keeps the first visible row inside the text and stops scrolling once the last
+// row is at the bottom.
+func (ta *TextArea) clampTop(rows int) {
+	ta.top.line = max(0, min(ta.top.line, len(ta.lines)-1))
+	ta.top.row = max(0, min(ta.top.row, ta.rowCount(ta.top.line)-1))
+	last := len(ta.lines) - 1
+	maxTop := ta.stepRows(rowRef{line: last, row: ta.rowCount(last) - 1}, -(rows - 1))
+	if maxTop.before(ta.top) {
+		ta.top = maxTop
+	}
+}
+
+// scrollToCaret scrolls the least amount that shows the caret row.
+func (ta *TextArea) scrollToCaret(rows int) {
+	cr := ta.rowOf(ta.caret)
+	if cr.before(ta.top) {
+		ta.top = cr
+		return
+	}
+	if ta.stepRows(ta.top, rows-1).before(cr) {
+		ta.top = ta.stepRows(cr, -(rows - 1))
+	}
+}
+
+// posAt returns the text position under the layout point (x, y).
+func (ta *TextArea) posAt(face text.Face, x, y float64) TextPos {
+	inner, lineH, _ := ta.view(face)
+	r := ta.stepRows(ta.top, int(math.Floor((y-inner.Y)/lineH)))
+	return TextPos{Line: r.line, Col: ta.colAt(r, x-inner.X)}
+}
+
+// moveRows moves the caret n rows down (up for negative n), keeping its x.
+// Moving past the first or last row goes to the start or end of the text.
+func (ta *TextArea) moveRows(n int, extend bool) {
+	cr := ta.rowOf(ta.caret)
+	goal := ta.goalX
+	if goal < 0 {
+		goal = ta.rowX(cr, ta.caret.Col)
+	}
+	target := ta.stepRows(cr, n)
+	p := TextPos{Line: target.line, Col: ta.colAt(target, goal)}
+	if target == cr {
+		p = TextPos{}
+		if n > 0 {
+			p = TextPos{Line: len(ta.lines) - 1, Col: len(ta.lines[len(ta.lines)-1].text)}
+		}
+	}
+	ta.moveCaret(p, extend)
+	ta.goalX = goal
+}
+
+// TextAreaTheme controls text area drawing colors.
+type TextAreaTheme struct {
+	Fill        colors.Color
+	Stroke      colors.Color
+	FocusStroke colors.Color
+	Text        colors.Color
+	Caret       colors.Color
+	Selection   colors.Color
+	Scrollbar   colors.Color
+}
+
+// DefaultTextAreaTheme returns the default text area theme.
+func DefaultTextAreaTheme() TextAreaTheme {
+	return TextAreaTheme{
+		Fill:        colors.HexOr("#1e1e1e", colors.RGB(30, 30, 30)),
+		Stroke:      colors.HexOr("#666", colors.RGB(102, 102, 102)),
+		FocusStroke: colors.HexOr("#4a9eff", colors.RGB(74, 158, 255)),
+		Text:        colors.HexOr("#eee", colors.RGB(238, 238, 238)),
+		Caret:       colors.HexOr("#eee", colors.RGB(238, 238, 238)),
+		Selection:   colors.HexOr("#264f78", colors.RGB(38, 79, 120)),
+		Scrollbar:   colors.HexOr("#555", colors.RGB(85, 85, 85)),
+	}
+}
+
+// Draw draws the visible rows with the selection and caret, and a scroll indicator.
+func (ta *TextArea) Draw(ctx *DrawContext) {
+	ta.draw(ctx.Dst, ctx.FaceFor(ta.Font), themeOr(ta.Theme, ctx.Theme.TextArea))
+}
+
+func (ta *TextArea) draw(dst *ebiten.Image, face text.Face, theme TextAreaTheme) {
+	b := ta.Bounds()
+	stroke := theme.Stroke
+	if ta.focused {
+		stroke = theme.FocusStroke
+	}
+	rendering.FillRect(dst, b.X, b.Y, b.W, b.H, theme.Fill)
+	rendering.DrawStrokeRect(dst, b.X, b.Y, b.W, b.H, 1.0, stroke)
+
+	inner, lineH, rows := ta.view(face)
+	if lineH <= 0 {
+		return
+	}
+	if ta.reveal {
+		ta.scrollToCaret(rows)
+		ta.reveal = false
+	}
+	ta.clampTop(rows)
+	ta.drawScrollbar(dst, inner, rows, theme)
+
+	clips := rendering.NewClipStack(dst)
+	target := clips.Push(inner)
+	if !clips.Visible() {
+		return
+	}
+	selStart, selEnd := ta.Selection()
+	caretRow := ta.rowOf(ta.caret)
+	spaceW := text.Advance(" ", face)
+	r := ta.top
+	// Rows are drawn down to the bottom edge; the last one may be partly visible.
+	for y := inner.Y; y < inner.Y+inner.H; y += lineH {
+		line := ta.lines[r.line].text
+		start, end := ta.rowRange(r)
+
+		if ta.focused && selStart.before(selEnd) && r.line >= selStart.Line && r.line <= selEnd.Line {
+			a, z := start, end
+			if r.line == selStart.Line {
+				a = max(a, selStart.Col)
+			}
+			if r.line == selEnd.Line {
+				z = min(z, selEnd.Col)
+			}
+			// A selected line break shows as a space at the end of the line.
+			extra := 0.0
+			if r.line < selEnd.Line && end == len(line) {
+				extra = spaceW
+			}
+			if a <= z {
+				ax := advanceTo(line[start:], a-start, face)
+				zx := advanceTo(line[start:], z-start, face)
+				if zx-ax+extra > 0 {
+					rendering.FillRect(target, inner.X+ax, y, zx-ax+extra, lineH, theme.Selection)
+				}
+			}
+		}
+		rendering.DrawText(target, string(line[start:end]), face, int(inner.X), int(y), theme.Text)
+		if ta.focused && r == caretRow {
+			cx := inner.X + advanceTo(line[start:], ta.caret.Col-start, face)
+			rendering.FillRect(target, cx, y, 1, lineH, theme.Caret)
+		}
+
+		next := ta.stepRows(r, 1)
+		if next == r {
+			break
+		}
+		r = next
+	}
+}
+
+// drawScrollbar draws a thumb showing the scroll position. Lines below the view are not
+// wrapped, so its size and position count lines rather than rows.
+func (ta *TextArea) drawScrollbar(dst *ebiten.Image, inner layout.Rect, rows int, theme TextAreaTheme) {
+	n := len(ta.lines)
+	if n <= rows && ta.top == (rowRef{}) {
+		return
+	}
+	h := max(inner.H*float64(rows)/float64(max(n, rows)), 20)
+	y := inner.Y + (inner.H-h)*float64(ta.top.line)/float64(max(n-1, 1))
+	rendering.FillRect(dst, inner.X+inner.W+1, y, textAreaScrollbar-2, h, theme.Scrollbar)
+}
+
+// HandleEvent edits the text: typed characters replace the selection, pressing places the
+// caret, dragging selects and the wheel scrolls.
+func (ta *TextArea) HandleEvent(e *Event) {
+	face := ta.ui.faceFor(ta.Font)
+	hasFace := face != nil
+	switch e.Type {
+	case EventFocus:
+		ta.focused = true
+	case EventBlur:
+		ta.focused = false
+		ta.selecting = false
+		ta.anchor = ta.caret
+	case EventMouseDown:
+		if !e.isPrimaryPress() || !hasFace {
+			return
+		}
+		ta.moveCaret(ta.posAt(face, e.X, e.Y), e.Mods.Has(ModShift))
+		ta.selecting = true
+		ta.ui.SetPointerCapture(ta)
+	case EventMouseMove:
+		if ta.selecting && hasFace {
+			ta.moveCaret(ta.posAt(face, e.X, e.Y), true)
+		}
+	case EventMouseUp:
+		ta.selecting = false
+	case EventWheel:
+		if !hasFace {
+			return
+		}
+		_, _, rows := ta.view(face)
+		old := ta.top
+		ta.top = ta.stepRows(ta.top, -int(math.Round(e.WheelY*textAreaWheelRows)))
+		ta.clampTop(rows)
+		if ta.top != old {
+			e.StopPropagation()
+		}
+	case EventTextInput:
+		ta.insert(e.Text)
+		e.StopPropagation()
+	case EventKeyDown:
+		if hasFace && ta.handleKey(face, e) {
+			e.StopPropagation()
+		}
+	}
+}
+
+// handleKey applies caret movement, deletion and Enter and reports whether the key was used.
+func (ta *TextArea) handleKey(face text.Face, e *Event) bool {
+	_, _, rows := ta.view(face)
+	shift := e.Mods.Has(ModShift)
+	ctrl := e.Mods.Has(ModCtrl)
+	word := ctrl || e.Mods.Has(ModAlt)
+	start, end := ta.Selection()
+	selected := start.before(end)
+	switch e.Key {
+	case ebiten.KeyArrowLeft:
+		switch {
+		case selected && !shift:
+			ta.moveCaret(start, false)
+		case word:
+			ta.moveCaret(ta.wordLeft(ta.caret), shift)
+		default:
+			ta.moveCaret(ta.left(ta.caret), shift)
+		}
+	case ebiten.KeyArrowRight:
+		switch {
+		case selected && !shift:
+			ta.moveCaret(end, false)
+		case word:
+			ta.moveCaret(ta.wordRight(ta.caret), shift)
+		default:
+			ta.moveCaret(ta.right(ta.caret), shift)
+		}
+	case ebiten.KeyArrowUp:
+		ta.moveRows(-1, shift)
+	case ebiten.KeyArrowDown:
+		ta.moveRows(1, shift)
+	case ebiten.KeyPageUp:
+		ta.top = ta.stepRows(ta.top, -rows)
+		ta.moveRows(-rows, shift)
+	case ebiten.KeyPageDown:
+		ta.top = ta.stepRows(ta.top, rows)
+		ta.moveRows(rows, shift)
+	case ebiten.KeyHome:
+		if ctrl {
+			ta.moveCaret(TextPos{}, shift)
+		} else {
+			rowStart, _ := ta.rowRange(ta.rowOf(ta.caret))
+			ta.moveCaret(TextPos{Line: ta.caret.Line, Col: rowStart}, shift)
+		}
+	case ebiten.KeyEnd:
+		if ctrl {
+			last := len(ta.lines) - 1
+			ta.moveCaret(TextPos{Line: last, Col: len(ta.lines[last].text)}, shift)
+		} else {
+			r := ta.rowOf(ta.caret)
+			ta.moveCaret(TextPos{Line: r.line, Col: ta.colAt(r, math.Inf(1))}, shift)
+		}
+	case ebiten.KeyBackspace:
+		switch {
+		case selected:
+			ta.deleteRange(start, end)
+		case word:
+			ta.deleteRange(ta.wordLeft(ta.caret), ta.caret)
+		default:
+			ta.deleteRange(ta.left(ta.caret), ta.caret)
+		}
+	case ebiten.KeyDelete:
+		switch {
+		case selected:
+			ta.deleteRange(start, end)
+		case word:
+			ta.deleteRange(ta.caret, ta.wordRight(ta.caret))
+		default:
+			ta.deleteRange(ta.caret, ta.right(ta.caret))
+		}
+	case ebiten.KeyA:
+		if !ctrl && !e.Mods.Has(ModMeta) {
+			return false
+		}
+		ta.SelectAll()
+	case ebiten.KeyEnter, ebiten.KeyNumpadEnter:
+		ta.insert("\n")
+	case ebiten.KeySpace:
+		// The space itself arrives as EventTextInput; keep the key from activating ancestors.
+	default:
+		return false
+	}
+	return true
+}
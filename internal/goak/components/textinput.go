@@ -0,0 +1,376 @@
+package components
+
+import (
+	"goak/internal/goak/colors"
+	"goak/internal/goak/layout"
+	"goak/internal/goak/rendering"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// TextInput is a single-line text field. Click it or Tab to it, then type; the caret and
+// selection follow the usual conventions (Shift extends the selection, Ctrl or Alt moves
+// by word, Ctrl+A selects all, Enter submits).
+// Create with NewTextInput; add with panel.AddTextInput(input).
+type TextInput struct {
+	Element
+	Placeholder string          // shown while the field is empty
+	MaxLength   int             // maximum number of characters; 0 means no limit
+	Password    bool            // draw every character as a bullet
+	Font        *Font           // nil uses the UI default font
+	Theme       *TextInputTheme // nil uses the UI theme
+	OnChanged   func(text string)
+	OnSubmit    func(text string) // called when Enter is pressed
+
+	text      []rune
+	caret     int     // caret position, in characters
+	anchor    int     // other end of the selection; equals caret when nothing is selected
+	scrollX   float64 // how far the text is scrolled left to keep the caret visible; set after layout
+	focused   bool
+	selecting bool // mouse button held after pressing in the field
+}
+
+const (
+	textInputPaddingX = 6.0
+	textInputPaddingY = 4.0
+	textInputMinWidth = 120.0
+	passwordMask      = '•'
+)
+
+// NewTextInput creates a standalone text input. Add it with panel.AddTextInput(input).
+func NewTextInput(width, height layout.Size, placeholder string) *TextInput {
+	ti := &TextInput{Placeholder: placeholder}
+	ti.Init(layout.NewContainer(width, height))
+	ti.SetFocusable(true)
+	ti.c.Measure = ti.measure
+	return ti
+}
+
+// measure reports the placeholder size (at least textInputMinWidth wide) plus padding.
+func (ti *TextInput) measure(float64) (float64, float64) {
+	tw, th := measureText(ti.ui, ti.Font, ti.Placeholder)
+	if th == 0 {
+		_, th = measureText(ti.ui, ti.Font, "M")
+	}
+	return max(tw, textInputMinWidth) + textInputPaddingX*2, th + textInputPaddingY*2
+}
+
+// Text returns the current text.
+func (ti *TextInput) Text() string {
+	return string(ti.text)
+}
+
+// SetText replaces the text (cut to MaxLength) and moves the caret to the end.
+// It does not call OnChanged.
+func (ti *TextInput) SetText(s string) {
+	ti.text = []rune(s)
+	if ti.MaxLength > 0 && len(ti.text) > ti.MaxLength {
+		ti.text = ti.text[:ti.MaxLength]
+	}
+	ti.caret = len(ti.text)
+	ti.anchor = ti.caret
+}
+
+// SelectAll selects the whole text.
+func (ti *TextInput) SelectAll() {
+	ti.anchor = 0
+	ti.caret = len(ti.text)
+}
+
+// Selection returns the selected range as character offsets; start == end when nothing
+// is selected, in which case both are the caret position.
+func (ti *TextInput) Selection() (start, end int) {
+	return min(ti.caret, ti.anchor), max(ti.caret, ti.anchor)
+}
+
+// SelectedText returns the selected part of the text.
+func (ti *TextInput) SelectedText() string {
+	start, end := ti.Selection()
+	return string(ti.text[start:end])
+}
+
+// insert replaces the selection with s, dropping control characters and whatever does not
+// fit in MaxLength.
+func (ti *TextInput) insert(s string) {
+	runes := []rune(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s))
+	start, end := ti.Selection()
+	if ti.MaxLength > 0 {
+		room := max(ti.MaxLength-(len(ti.text)-(end-start)), 0)
+		if len(runes) > room {
+			runes = runes[:room]
+		}
+	}
+	if len(runes) == 0 && start == end {
+		return
+	}
+	ti.text = slices.Concat(ti.text[:start], runes, ti.text[end:])
+	ti.caret = start + len(runes)
+	ti.anchor = ti.caret
+	ti.changed()
+}
+
+// deleteRange removes the characters in [start, end), clamped to the text, and leaves the
+// caret at start.
+func (ti *TextInput) deleteRange(start, end int) {
+	start, end = max(start, 0), min(end, len(ti.text))
+	if start >= end {
+		return
+	}
+	ti.text = slices.Delete(ti.text, start, end)
+	ti.caret = start
+	ti.anchor = start
+	ti.changed()
+}
+
+func (ti *TextInput) changed() {
+	if ti.OnChanged != nil {
+		ti.OnChanged(string(ti.text))
+	}
+}
+
+// moveCaret moves the caret to pos; with extend the selection anchor stays put.
+func (ti *TextInput) moveCaret(pos int, extend bool) {
+	ti.caret = max(0, min(pos, len(ti.text)))
+	if !extend {
+		ti.anchor = ti.caret
+	}
+}
+
+// wordLeft returns the start of the word before i. Password fields have no words.
+func (ti *TextInput) wordLeft(i int) int {
+	if ti.Password {
+		return 0
+	}
+	return wordStart(ti.text, i)
+}
+
+// wordRight returns the end of the word after i. Password fields have no words.
+func (ti *TextInput) wordRight(i int) int {
+	if ti.Password {
+		return len(ti.text)
+	}
+	return wordEnd(ti.text, i)
+}
+
+// displayText returns the text as drawn: bullets instead of characters for passwords.
+func (ti *TextInput) displayText() []rune {
+	if !ti.Password {
+		return ti.text
+	}
+	mask := make([]rune, len(ti.text))
+	for i := range mask {
+		mask[i] = passwordMask
+	}
+	return mask
+}
+
+// indexAt returns the caret position closest to layout x.
+func (ti *TextInput) indexAt(x float64) int {
+	face := ti.ui.faceFor(ti.Font)
+	if face == nil {
+		return len(ti.text)
+	}
+	local := x - (ti.Bounds().X + textInputPaddingX) + ti.scrollX
+	return nearestIndex(ti.displayText(), local, face)
+}
+
+// TextInputTheme controls text input drawing colors.
+type TextInputTheme struct {
+	Fill        colors.Color
+	Stroke      colors.Color
+	FocusStroke colors.Color
+	Text        colors.Color
+	Placeholder colors.Color
+	Caret       colors.Color
+	Selection   colors.Color
+}
+
+// DefaultTextInputTheme returns the default text input theme.
+func DefaultTextInputTheme() TextInputTheme {
+	return TextInputTheme{
+		Fill:        colors.HexOr("#1e1e1e", colors.RGB(30, 30, 30)),
+		Stroke:      colors.HexOr("#666", colors.RGB(102, 102, 102)),
+		FocusStroke: colors.HexOr("#4a9eff", colors.RGB(74, 158, 255)),
+		Text:        colors.HexOr("#eee", colors.RGB(238, 238, 238)),
+		Placeholder: colors.HexOr("#888", colors.RGB(136, 136, 136)),
+		Caret:       colors.HexOr("#eee", colors.RGB(238, 238, 238)),
+		Selection:   colors.HexOr("#264f78", colors.RGB(38, 79, 120)),
+	}
+}
+
+// Draw draws the field, its text or placeholder, the selection and the caret.
+func (ti *TextInput) Draw(ctx *DrawContext) {
+	ti.draw(ctx.Dst, ctx.FaceFor(ti.Font), themeOr(ti.Theme, ctx.Theme.TextInput))
+}
+
+func (ti *TextInput) draw(dst *ebiten.Image, face text.Face, theme TextInputTheme) {
+	b := ti.Bounds()
+	stroke := theme.Stroke
+	if ti.focused {
+		stroke = theme.FocusStroke
+	}
+	rendering.FillRect(dst, b.X, b.Y, b.W, b.H, theme.Fill)
+	rendering.DrawStrokeRect(dst, b.X, b.Y, b.W, b.H, 1.0, stroke)
+
+	inner := layout.Rect{X: b.X + textInputPaddingX, Y: b.Y, W: b.W - textInputPaddingX*2, H: b.H}
+	clips := rendering.NewClipStack(dst)
+	target := clips.Push(inner)
+	if !clips.Visible() {
+		return
+	}
+
+	lineH := lineHeight(face)
+	ty := b.Y + (b.H-lineH)/2
+
+	display := ti.displayText()
+	x0 := inner.X - ti.scrollX
+
+	if len(display) == 0 && ti.Placeholder != "" {
+		rendering.DrawText(target, ti.Placeholder, face, int(inner.X), int(ty), theme.Placeholder)
+	}
+	if start, end := ti.Selection(); ti.focused && start < end {
+		sx := advanceTo(display, start, face)
+		ex := advanceTo(display, end, face)
+		rendering.FillRect(target, x0+sx, ty, ex-sx, lineH, theme.Selection)
+	}
+	rendering.DrawText(target, string(display), face, int(x0), int(ty), theme.Text)
+	if ti.focused {
+		cx := x0 + advanceTo(display, ti.caret, face)
+		rendering.FillRect(target, cx, ty, 1, lineH, theme.Caret)
+	}
+}
+
+// laidOut scrolls the text so the caret is visible at the new bounds, before the field is
+// drawn or hit-tested.
+func (ti *TextInput) laidOut() {
+	face := ti.ui.faceFor(ti.Font)
+	if face == nil {
+		return
+	}
+	ti.scrollToCaret(ti.displayText(), face, ti.Bounds().W-textInputPaddingX*2)
+}
+
+// scrollToCaret adjusts scrollX so the caret is inside a view viewW wide, without
+// scrolling past the end of the text.
+func (ti *TextInput) scrollToCaret(display []rune, face text.Face, viewW float64) {
+	cx := advanceTo(display, ti.caret, face)
+	if cx-ti.scrollX > viewW-1 {
+		ti.scrollX = cx - viewW + 1
+	}
+	if cx < ti.scrollX {
+		ti.scrollX = cx
+	}
+	total := advanceTo(display, len(display), face)
+	ti.scrollX = max(0, min(ti.scrollX, total-viewW+1))
+}
+
+// HandleEvent edits the text: typed characters replace the selection, pressing places the
+// caret and dragging selects.
+func (ti *TextInput) HandleEvent(e *Event) {
+	switch e.Type {
+	case EventFocus:
+		ti.focused = true
+		// Tabbing into a field selects its text, as in most toolkits.
+		if ti.ui != nil && ti.ui.focusVisible {
+			ti.SelectAll()
+		}
+	case EventBlur:
+		ti.focused = false
+		ti.selecting = false
+		ti.anchor = ti.caret
+	case EventMouseDown:
+		if !e.isPrimaryPress() {
+			return
+		}
+		ti.moveCaret(ti.indexAt(e.X), e.Mods.Has(ModShift))
+		ti.selecting = true
+		if ti.ui != nil {
+			ti.ui.SetPointerCapture(ti)
+		}
+	case EventMouseMove:
+		if ti.selecting {
+			ti.moveCaret(ti.indexAt(e.X), true)
+		}
+	case EventMouseUp:
+		ti.selecting = false
+	case EventTextInput:
+		ti.insert(e.Text)
+		e.StopPropagation()
+	case EventKeyDown:
+		if ti.handleKey(e) {
+			e.StopPropagation()
+		}
+	}
+}
+
+// handleKey applies caret movement and deletion keys and reports whether the key was used.
+func (ti *TextInput) handleKey(e *Event) bool {
+	shift := e.Mods.Has(ModShift)
+	word := e.Mods.Has(ModCtrl) || e.Mods.Has(ModAlt)
+	start, end := ti.Selection()
+	switch e.Key {
+	case ebiten.KeyArrowLeft:
+		switch {
+		case start < end && !shift:
+			ti.moveCaret(start, false)
+		case word:
+			ti.moveCaret(ti.wordLeft(ti.caret), shift)
+		default:
+			ti.moveCaret(ti.caret-1, shift)
+		}
+	case ebiten.KeyArrowRight:
+		switch {
+		case start < end && !shift:
+			ti.moveCaret(end, false)
+		case word:
+			ti.moveCaret(ti.wordRight(ti.caret), shift)
+		default:
+			ti.moveCaret(ti.caret+1, shift)
+		}
+	case ebiten.KeyHome:
+		ti.moveCaret(0, shift)
+	case ebiten.KeyEnd:
+		ti.moveCaret(len(ti.text), shift)
+	case ebiten.KeyBackspace:
+		switch {
+		case start < end:
+			ti.deleteRange(start, end)
+		case word:
+			ti.deleteRange(ti.wordLeft(ti.caret), ti.caret)
+		default:
+			ti.deleteRange(ti.caret-1, ti.caret)
+		}
+	case ebiten.KeyDelete:
+		switch {
+		case start < end:
+			ti.deleteRange(start, end)
+		case word:
+			ti.deleteRange(ti.caret, ti.wordRight(ti.caret))
+		default:
+			ti.deleteRange(ti.caret, ti.caret+1)
+		}
+	case ebiten.KeyA:
+		if !e.Mods.Has(ModCtrl) && !e.Mods.Has(ModMeta) {
+			return false
+		}
+		ti.SelectAll()
+	case ebiten.KeyEnter, ebiten.KeyNumpadEnter:
+		if ti.OnSubmit != nil {
+			ti.OnSubmit(string(ti.text))
+		}
+	case ebiten.KeySpace:
+		// The space itself arrives as EventTextInput; keep the key from activating ancestors.
+	default:
+		return false
+	}
+	return true
+}
@@ -1,13 +1,77 @@
 package components
 
-import "github.com/hajimehoshi/ebiten/v2/text/v2"
+import (
+	"sort"
+	"unicode"
 
-func textTopY(label string, face text.GoTextFace, rowY, rowH float64) int {
-	_, th := text.Measure(label, &face, 0)
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+func textTopY(label string, face text.Face, rowY, rowH float64) int {
+	_, th := text.Measure(label, face, 0)
 	return int(rowY + (rowH-th)/2)
 }
 
-func textHeight(label string, face text.GoTextFace) float64 {
-	_, th := text.Measure(label, &face, 0)
+// measureText returns the label size in font (nil = the UI default), or zero before the
+// UI has fonts.
+func measureText(u *UI, font *Font, label string) (w, h float64) {
+	face := u.faceFor(font)
+	if face == nil {
+		return 0, 0
+	}
+	return text.Measure(label, face, 0)
+}
+
+// lineHeight returns the height of one line of text in face.
+func lineHeight(face text.Face) float64 {
+	m := face.Metrics()
+	return m.HAscent + m.HDescent
+}
+
+func textHeight(label string, face text.Face) float64 {
+	_, th := text.Measure(label, face, 0)
 	return th
 }
+
+// advanceTo returns the x offset of position i from the start of runes.
+func advanceTo(runes []rune, i int, face text.Face) float64 {
+	return text.Advance(string(runes[:i]), face)
+}
+
+// nearestIndex returns the position in runes (0..len) whose x offset is closest to x.
+func nearestIndex(runes []rune, x float64, face text.Face) int {
+	// Offsets grow with i, so find the first position past x, then pick the closer side.
+	i := sort.Search(len(runes)+1, func(i int) bool {
+		return advanceTo(runes, i, face) >= x
+	})
+	if i > 0 && (i > len(runes) || x-advanceTo(runes, i-1, face) < advanceTo(runes, i, face)-x) {
+		i--
+	}
+	return i
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// wordStart returns the start of the word before position i.
+func wordStart(runes []rune, i int) int {
+	for i > 0 && !isWordRune(runes[i-1]) {
+		i--
+	}
+	for i > 0 && isWordRune(runes[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordEnd returns the end of the word after position i.
+func wordEnd(runes []rune, i int) int {
+	for i < len(runes) && !isWordRune(runes[i]) {
+		i++
+	}
+	for i < len(runes) && isWordRune(runes[i]) {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,186 @@
+package components
+
+import "goak/internal/goak/colors"
+
+// Theme styles the whole UI: the colors of every widget kind, the default font and shared
+// metrics. Switch it at runtime with ui.SetTheme. A widget whose Theme field is set keeps
+// that override whatever the UI theme is.
+type Theme struct {
+	Name        string
+	Background  colors.Color // window background behind the root
+	Font        Font         // default font; zero fields keep the font registry default
+	Metrics     ThemeMetrics
+	Panel       PanelTheme
+	Button      ButtonTheme
+	Checkbox    CheckboxTheme
+	Radio       RadioTheme
+	Slider      SliderTheme
+	Dropdown    DropdownTheme
+	Menu        MenuTheme
+	ContextMenu ContextMenuTheme
+	Scroll      ScrollTheme
+	TextInput   TextInputTheme
+	TextArea    TextAreaTheme
+	Label       LabelTheme
+	Focus       FocusTheme
+}
+
+// ThemeMetrics holds sizes shared by widgets.
+type ThemeMetrics struct {
+	ScrollbarThickness float64 // width of vertical and height of horizontal scrollbars
+	ScrollThumbMin     float64 // shortest scrollbar thumb
+}
+
+// DefaultThemeMetrics returns the default metrics.
+func DefaultThemeMetrics() ThemeMetrics {
+	return ThemeMetrics{
+		ScrollbarThickness: 10,
+		ScrollThumbMin:     20,
+	}
+}
+
+// DarkTheme returns the built-in dark theme, made of the Default*Theme of every widget.
+// It is the theme of a new UI.
+func DarkTheme() Theme {
+	return Theme{
+		Name:        "dark",
+		Background:  colors.Black,
+		Metrics:     DefaultThemeMetrics(),
+		Panel:       DefaultPanelTheme(),
+		Button:      DefaultButtonTheme(),
+		Checkbox:    DefaultCheckboxTheme(),
+		Radio:       DefaultRadioTheme(),
+		Slider:      DefaultSliderTheme(),
+		Dropdown:    DefaultDropdownTheme(),
+		Menu:        DefaultMenuTheme(),
+		ContextMenu: DefaultContextMenuTheme(),
+		Scroll:      DefaultScrollTheme(),
+		TextInput:   DefaultTextInputTheme(),
+		TextArea:    DefaultTextAreaTheme(),
+		Label:       DefaultLabelTheme(),
+		Focus:       DefaultFocusTheme(),
+	}
+}
+
+// LightTheme returns the built-in light theme.
+func LightTheme() Theme {
+	bg := colors.HexOr("#f3f3f3", colors.RGB(243, 243, 243))
+	surface := colors.HexOr("#ffffff", colors.RGB(255, 255, 255))
+	control := colors.HexOr("#e8e8e8", colors.RGB(232, 232, 232))
+	hover := colors.HexOr("#dcdcdc", colors.RGB(220, 220, 220))
+	border := colors.HexOr("#b0b0b0", colors.RGB(176, 176, 176))
+	text := colors.HexOr("#1e1e1e", colors.RGB(30, 30, 30))
+	muted := colors.HexOr("#8a8a8a", colors.RGB(138, 138, 138))
+	accent := colors.HexOr("#1a73e8", colors.RGB(26, 115, 232))
+	return Theme{
+		Name:       "light",
+		Background: bg,
+		Metrics:    DefaultThemeMetrics(),
+		Panel: PanelTheme{
+			DefaultFill: bg,
+			Stroke:      border,
+		},
+		Button: ButtonTheme{
+			Fill:   control,
+			Stroke: border,
+			Text:   text,
+		},
+		Checkbox: CheckboxTheme{
+			BoxFill:      surface,
+			BoxStroke:    border,
+			CheckFill:    accent,
+			Text:         text,
+			HoverOverlay: colors.RGBA(0, 0, 0, 20),
+		},
+		Radio: RadioTheme{
+			CircleFill:   surface,
+			CircleStroke: border,
+			SelectedFill: accent,
+			Text:         text,
+			HoverOverlay: colors.RGBA(0, 0, 0, 20),
+		},
+		Slider: SliderTheme{
+			TrackFill:   control,
+			TrackStroke: border,
+			FillColor:   accent,
+			ThumbFill:   surface,
+			ThumbStroke: border,
+			Text:        text,
+		},
+		Dropdown: DropdownTheme{
+			Fill:      surface,
+			Stroke:    border,
+			Hover:     hover,
+			Selected:  accent,
+			Text:      text,
+			ArrowFill: muted,
+		},
+		Menu: MenuTheme{
+			Fill:         surface,
+			Stroke:       border,
+			Hover:        control,
+			Active:       hover,
+			Text:         text,
+			DisabledText: muted,
+			Separator:    border,
+		},
+		ContextMenu: ContextMenuTheme{
+			Fill:         surface,
+			Stroke:       border,
+			Hover:        control,
+			Text:         text,
+			DisabledText: muted,
+			Separator:    border,
+		},
+		Scroll: ScrollTheme{
+			Track:      colors.RGBA(0, 0, 0, 20),
+			Thumb:      colors.HexOr("#c1c1c1", colors.RGB(193, 193, 193)),
+			ThumbHover: colors.HexOr("#a8a8a8", colors.RGB(168, 168, 168)),
+			ThumbDrag:  accent,
+		},
+		TextInput: TextInputTheme{
+			Fill:        surface,
+			Stroke:      border,
+			FocusStroke: accent,
+			Text:        text,
+			Placeholder: muted,
+			Caret:       text,
+			Selection:   colors.HexOr("#add6ff", colors.RGB(173, 214, 255)),
+		},
+		TextArea: TextAreaTheme{
+			Fill:        surface,
+			Stroke:      border,
+			FocusStroke: accent,
+			Text:        text,
+			Caret:       text,
+			Selection:   colors.HexOr("#add6ff", colors.RGB(173, 214, 255)),
+			Scrollbar:   colors.HexOr("#c1c1c1", colors.RGB(193, 193, 193)),
+		},
+		Label: LabelTheme{
+			Text: text,
+		},
+		Focus: FocusTheme{
+			Ring:   accent,
+			Width:  2,
+			Offset: 2,
+		},
+	}
+}
+
+// SetTheme switches the theme of the whole UI.
+func (u *UI) SetTheme(theme Theme) {
+	u.theme = theme
+}
+
+// Theme returns the current theme.
+func (u *UI) Theme() Theme {
+	return u.theme
+}
+
+// themeOr returns the widget override when set, else the UI theme value.
+func themeOr[T any](override *T, theme T) T {
+	if override != nil {
+		return *override
+	}
+	return theme
+}
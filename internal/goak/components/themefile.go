@@ -0,0 +1,399 @@
+package components
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goak/internal/goak/colors"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Theme files are JSON objects; other formats such as TOML are not supported, and
+// LoadThemeFile rejects files with a .toml extension. Keys are the Theme field names in
+// lower camel case, nested for the widget themes and their fields:
+//
+//	{
+//	  "name": "ocean",
+//	  "base": "dark",
+//	  "palette": {"accent": "#1a73e8", "ink": "white"},
+//	  "background": "#101820",
+//	  "font": {"family": "M PLUS 1p", "size": 18, "weight": "medium", "style": "normal"},
+//	  "metrics": {"scrollbarThickness": 12},
+//	  "button": {"fill": "$accent", "text": "$ink"},
+//	  "focus": {"ring": "$accent", "width": 2}
+//	}
+//
+// "base" is the theme the file starts from: "dark" (the default), "light", or, for files
+// loaded with LoadThemeFile, the path of another theme file relative to this one. Keys left
+// out keep the value of the base. "palette" names colors for the file and for files that
+// use it as their base; its entries may refer to the palette of the base file.
+//
+// A color is "#RGB", "#RGBA", "#RRGGBB" or "#RRGGBBAA", a color name known to
+// colors.ByName ("white", "skyblue", ...), or "$name" for a palette entry. A font weight is
+// a number from 100 to 900 or one of thin, light, regular, medium, semibold, bold, black;
+// a font style is normal or italic. Unknown keys and invalid values are reported as a
+// *ThemeError naming the key.
+
+// ThemeError reports an invalid entry in a theme file.
+type ThemeError struct {
+	File string // empty for themes parsed from memory
+	Key  string // dotted path of the entry, e.g. "button.fill"
+	Err  error
+}
+
+func (e *ThemeError) Error() string {
+	msg := fmt.Sprintf("%s: %v", e.Key, e.Err)
+	if e.Key == "" {
+		msg = e.Err.Error()
+	}
+	if e.File != "" {
+		return e.File + ": " + msg
+	}
+	return msg
+}
+
+func (e *ThemeError) Unwrap() error {
+	return e.Err
+}
+
+// BuiltinTheme returns the built-in theme called name ("dark" or "light").
+func BuiltinTheme(name string) (Theme, bool) {
+	switch name {
+	case "dark":
+		return DarkTheme(), true
+	case "light":
+		return LightTheme(), true
+	}
+	return Theme{}, false
+}
+
+// ParseTheme reads a theme from JSON data. Its base must be a built-in theme.
+func ParseTheme(data []byte) (Theme, error) {
+	t, _, err := (&themeLoader{}).parse(data, "")
+	return t, err
+}
+
+// LoadThemeFile reads a theme from a JSON file. Its base may be another theme file.
+// Only JSON is supported.
+func LoadThemeFile(path string) (Theme, error) {
+	t, _, err := (&themeLoader{}).load(path)
+	return t, err
+}
+
+// MarshalTheme returns t as indented JSON in the theme file format, with every key set.
+func MarshalTheme(t Theme) ([]byte, error) {
+	data, err := json.MarshalIndent(encodeTheme(reflect.ValueOf(t)), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// SaveThemeFile writes t to a JSON file that LoadThemeFile reads back.
+func SaveThemeFile(path string, t Theme) error {
+	data, err := MarshalTheme(t)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// themeLoader reads theme files, following base files and detecting cycles.
+type themeLoader struct {
+	loading []string
+}
+
+// load reads the theme file at path, returning the theme and its palette.
+func (l *themeLoader) load(path string) (Theme, map[string]colors.Color, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Theme{}, nil, err
+	}
+	if slices.Contains(l.loading, abs) {
+		return Theme{}, nil, &ThemeError{File: path, Key: "base", Err: errors.New("theme inherits from itself")}
+	}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return Theme{}, nil, &ThemeError{File: path, Err: errors.New("TOML theme files are not supported; use JSON")}
+	}
+	l.loading = append(l.loading, abs)
+	defer func() { l.loading = l.loading[:len(l.loading)-1] }()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, nil, err
+	}
+	return l.parse(data, path)
+}
+
+// parse reads a theme from data; file is the path it was read from, or "".
+func (l *themeLoader) parse(data []byte, file string) (Theme, map[string]colors.Color, error) {
+	fail := func(key string, err error) (Theme, map[string]colors.Color, error) {
+		return Theme{}, nil, &ThemeError{File: file, Key: key, Err: err}
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fail("", err)
+	}
+
+	theme := DarkTheme()
+	palette := make(map[string]colors.Color)
+	if v, ok := raw["base"]; ok {
+		base, ok := v.(string)
+		if !ok {
+			return fail("base", errors.New("expected a string"))
+		}
+		if t, ok := BuiltinTheme(base); ok {
+			theme = t
+		} else if file != "" {
+			t, p, err := l.load(filepath.Join(filepath.Dir(file), base))
+			if err != nil {
+				var te *ThemeError
+				if errors.As(err, &te) {
+					return Theme{}, nil, err
+				}
+				return fail("base", err)
+			}
+			theme, palette = t, p
+		} else {
+			return fail("base", fmt.Errorf("unknown theme %q", base))
+		}
+	}
+
+	if v, ok := raw["palette"]; ok {
+		entries, ok := v.(map[string]any)
+		if !ok {
+			return fail("palette", errors.New("expected an object"))
+		}
+		base := palette
+		palette = maps.Clone(base)
+		for _, name := range sortedKeys(entries) {
+			s, ok := entries[name].(string)
+			if !ok {
+				return fail("palette."+name, errors.New("expected a color string"))
+			}
+			c, err := parseThemeColor(s, base)
+			if err != nil {
+				return fail("palette."+name, err)
+			}
+			palette[name] = c
+		}
+	}
+
+	delete(raw, "base")
+	delete(raw, "palette")
+	d := themeDecoder{palette: palette}
+	if err := d.decode(reflect.ValueOf(&theme).Elem(), raw, ""); err != nil {
+		var te *ThemeError
+		errors.As(err, &te)
+		te.File = file
+		return Theme{}, nil, te
+	}
+	return theme, palette, nil
+}
+
+var (
+	colorType      = reflect.TypeFor[colors.Color]()
+	fontWeightType = reflect.TypeFor[FontWeight]()
+	fontStyleType  = reflect.TypeFor[FontStyle]()
+)
+
+var fontWeightNames = map[string]FontWeight{
+	"thin":     WeightThin,
+	"light":    WeightLight,
+	"regular":  WeightRegular,
+	"medium":   WeightMedium,
+	"semibold": WeightSemiBold,
+	"bold":     WeightBold,
+	"black":    WeightBlack,
+}
+
+// themeDecoder sets Theme fields from decoded JSON values.
+type themeDecoder struct {
+	palette map[string]colors.Color
+}
+
+// decode sets v from raw; key is the dotted path of v, used in errors.
+func (d *themeDecoder) decode(v reflect.Value, raw any, key string) error {
+	fail := func(format string, args ...any) error {
+		return &ThemeError{Key: key, Err: fmt.Errorf(format, args...)}
+	}
+	switch v.Type() {
+	case colorType:
+		s, ok := raw.(string)
+		if !ok {
+			return fail("expected a color string")
+		}
+		c, err := parseThemeColor(s, d.palette)
+		if err != nil {
+			return &ThemeError{Key: key, Err: err}
+		}
+		v.Set(reflect.ValueOf(c))
+		return nil
+	case fontWeightType:
+		switch w := raw.(type) {
+		case string:
+			weight, ok := fontWeightNames[strings.ToLower(w)]
+			if !ok {
+				return fail("unknown font weight %q", w)
+			}
+			v.SetInt(int64(weight))
+		case float64:
+			if w != 0 && (w < 100 || w > 900 || w != math.Trunc(w)) {
+				return fail("font weight %v is not a whole number from 100 to 900", w)
+			}
+			v.SetInt(int64(w))
+		default:
+			return fail("expected a font weight")
+		}
+		return nil
+	case fontStyleType:
+		switch raw {
+		case "normal":
+			v.SetInt(int64(StyleNormal))
+		case "italic":
+			v.SetInt(int64(StyleItalic))
+		default:
+			return fail("expected \"normal\" or \"italic\"")
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return fail("expected an object")
+		}
+		for _, k := range sortedKeys(obj) {
+			sub := k
+			if key != "" {
+				sub = key + "." + k
+			}
+			i := themeFieldIndex(v.Type(), k)
+			if i < 0 {
+				return &ThemeError{Key: sub, Err: errors.New("unknown key")}
+			}
+			if err := d.decode(v.Field(i), obj[k], sub); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return fail("expected a string")
+		}
+		v.SetString(s)
+	case reflect.Float64:
+		n, ok := raw.(float64)
+		if !ok {
+			return fail("expected a number")
+		}
+		if n < 0 {
+			return fail("must not be negative")
+		}
+		v.SetFloat(n)
+	default:
+		return fail("unsupported value")
+	}
+	return nil
+}
+
+// parseThemeColor parses a hex color, a color name or a $palette reference.
+func parseThemeColor(s string, palette map[string]colors.Color) (colors.Color, error) {
+	if name, ok := strings.CutPrefix(s, "$"); ok {
+		if c, ok := palette[name]; ok {
+			return c, nil
+		}
+		return colors.Color{}, fmt.Errorf("unknown palette color %q", name)
+	}
+	if strings.HasPrefix(s, "#") {
+		if c, ok := colors.ParseHex(s); ok {
+			return c, nil
+		}
+		return colors.Color{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	if c, ok := colors.ByName(s); ok {
+		return c, nil
+	}
+	return colors.Color{}, fmt.Errorf("unknown color %q", s)
+}
+
+// themeFieldIndex returns the index of the field of t named key, or -1.
+func themeFieldIndex(t reflect.Type, key string) int {
+	for i := range t.NumField() {
+		if themeKey(t.Field(i).Name) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// themeKey returns the file key of a field: its name with the first letter lowered.
+func themeKey(field string) string {
+	r := []rune(field)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// themeObject is a JSON object that keeps its keys in field order.
+type themeObject []themeEntry
+
+type themeEntry struct {
+	key   string
+	value any
+}
+
+func (o themeObject) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, e := range o {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		k, _ := json.Marshal(e.key)
+		v, err := json.Marshal(e.value)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(append(buf, k...), ':'), v...)
+	}
+	return append(buf, '}'), nil
+}
+
+// encodeTheme returns v as a value for json.Marshal in the theme file format.
+func encodeTheme(v reflect.Value) any {
+	switch v.Type() {
+	case colorType:
+		return v.Interface().(colors.Color).Hex()
+	case fontWeightType:
+		return v.Int()
+	case fontStyleType:
+		if FontStyle(v.Int()) == StyleItalic {
+			return "italic"
+		}
+		return "normal"
+	}
+	if v.Kind() != reflect.Struct {
+		return v.Interface()
+	}
+	obj := make(themeObject, 0, v.NumField())
+	for i := range v.NumField() {
+		obj = append(obj, themeEntry{key: themeKey(v.Type().Field(i).Name), value: encodeTheme(v.Field(i))})
+	}
+	return obj
+}
@@ -1,86 +1,367 @@
 package components
 
-import "goak/internal/goak/layout"
+import (
+	"cmp"
+	"goak/internal/goak/layout"
+	"goak/internal/goak/rendering"
+	"slices"
 
-// UI holds the root and all panels/buttons for layout and drawing.
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// UI holds the widget tree rooted at Root, and routes layout, drawing and input through it.
 type UI struct {
-	root         *layout.Container
-	rootEl       *Root
-	panels       []*Panel
-	buttons      []*Button
-	menus        []*MenuBar
-	checkboxes   []*Checkbox
-	radioGroups  []*RadioGroup
-	sliders      []*Slider
-	dropdowns    []*Dropdown
-	contextMenus []*ContextMenu
+	root         *Root
+	capture      Widget
+	hover        Widget
+	focus        Widget
+	focusVisible bool
+	menu         Widget // open context menu or active menu bar, which takes keyboard events
+	theme        Theme
+	fonts        *FontRegistry
+	accel        *Accelerators
+	paint        []paintItem // paint order of the last Layout, for drawing and hit-testing
+	altTap       bool        // Alt is down and no other key was pressed since
 }
 
 // NewUI creates a UI with an empty root. Use Root() to get the root element and build the tree.
 func NewUI() *UI {
-	root := layout.NewContainer(layout.AutoSize(), layout.AutoSize())
-	u := &UI{
-		root:    root,
-		panels:  nil,
-		buttons: nil,
-		menus:   nil,
-	}
-	u.rootEl = &Root{ui: u, c: root, Scale: 1}
+	u := &UI{theme: DarkTheme(), accel: newAccelerators()}
+	u.root = &Root{Scale: 1}
+	u.root.Init(layout.NewContainer(layout.AutoSize(), layout.AutoSize()))
+	u.root.self = u.root
+	u.root.ui = u
 	return u
 }
 
 // Root returns the root element. Build the tree with root.CreatePanel(...), then panel.CreateButton(...) etc.
 // Root.Scale (default 1) scales the whole UI when changed.
 func (u *UI) Root() *Root {
-	return u.rootEl
+	return u.root
+}
+
+// Walk calls fn for every widget in tree order, parents before children.
+// Returning false from fn skips the widget's children.
+func (u *UI) Walk(fn func(w Widget) bool) {
+	walk(u.root, fn)
+}
+
+func walk(w Widget, fn func(w Widget) bool) {
+	if !fn(w) {
+		return
+	}
+	for _, child := range w.element().children {
+		walk(child, fn)
+	}
+}
+
+// layoutSyncer is implemented by widgets that update their layout nodes before each Layout.
+type layoutSyncer interface {
+	syncLayout()
 }
 
-// Panels returns all panels (for rendering).
-func (u *UI) Panels() []*Panel {
-	return u.panels
+// layoutWatcher is implemented by widgets that update state derived from their bounds after
+// each Layout, so that drawing and hit-testing see the same state.
+type layoutWatcher interface {
+	laidOut()
 }
 
-// Buttons returns all buttons (for rendering and hit-test).
-func (u *UI) Buttons() []*Button {
-	return u.buttons
+// Layout lays out the tree for a viewport of viewW x viewH (in layout units).
+func (u *UI) Layout(viewW, viewH float64) {
+	u.Walk(func(w Widget) bool {
+		if s, ok := w.(layoutSyncer); ok {
+			s.syncLayout()
+		}
+		return true
+	})
+	layout.Layout(u.root.c, viewW, viewH)
+	u.paint = u.paintOrder(u.paint[:0])
+	for _, it := range u.paint {
+		if w, ok := it.w.(layoutWatcher); ok && !it.foreground {
+			w.laidOut()
+		}
+	}
 }
 
-// MenuBars returns all menu bars (for rendering and hit-test).
-func (u *UI) MenuBars() []*MenuBar {
-	return u.menus
+// paintItem is one step of the paint order: a widget, or the foreground drawn over its children.
+type paintItem struct {
+	w          Widget
+	foreground bool
+}
+
+// paintOrder appends the tree to items in the order it is painted: parents before
+// children, siblings in tree order sorted by z-index, and floating subtrees (popups) after
+// everything else so they cover the widgets below them. Floating subtrees are sorted by
+// Floating.ZIndex; one opened from inside another popup is painted above it.
+func (u *UI) paintOrder(items []paintItem) []paintItem {
+	var floating []Widget
+	var visit func(w Widget)
+	visit = func(w Widget) {
+		items = append(items, paintItem{w: w})
+		for _, child := range byZIndex(w.element().children) {
+			if child.Container().Floating != nil {
+				floating = append(floating, child)
+				continue
+			}
+			visit(child)
+		}
+		if _, ok := w.(Foreground); ok {
+			items = append(items, paintItem{w: w, foreground: true})
+		}
+	}
+	visit(u.root)
+	for len(floating) > 0 {
+		layer := byZIndex(floating)
+		floating = nil
+		for _, w := range layer {
+			visit(w)
+		}
+	}
+	return items
+}
+
+// byZIndex returns widgets stably sorted by z-index, keeping tree order for equal values.
+func byZIndex(widgets []Widget) []Widget {
+	sorted := slices.Clone(widgets)
+	slices.SortStableFunc(sorted, func(a, b Widget) int {
+		return cmp.Compare(a.element().ZIndex(), b.element().ZIndex())
+	})
+	return sorted
+}
+
+// painted returns the paint order computed by the last Layout, which drawing and
+// hit-testing share, or computes it before the first Layout.
+func (u *UI) painted() []paintItem {
+	if u.paint == nil {
+		return u.paintOrder(nil)
+	}
+	return u.paint
+}
+
+// Draw paints the tree into dst in the paint order of the last Layout. Each widget draws
+// into the area its clipping ancestors leave visible.
+func (u *UI) Draw(dst *ebiten.Image) {
+	clips := rendering.NewClipStack(dst)
+	ctx := &DrawContext{Face: u.Face(), Theme: &u.theme, ui: u}
+	for _, item := range u.painted() {
+		ctx.Dst = clips.Push(item.w.Container().Clip)
+		if clips.Visible() {
+			if item.foreground {
+				item.w.(Foreground).DrawForeground(ctx)
+			} else {
+				item.w.Draw(ctx)
+			}
+		}
+		clips.Pop()
+	}
 }
 
-// ButtonClicked runs the OnClick callback for the button at index. No-op if index is out of range or OnClick is nil.
-func (u *UI) ButtonClicked(index int) {
-	if index < 0 || index >= len(u.buttons) {
+// WidgetAt returns the topmost widget at the point, in reverse paint order as of the last
+// Layout. It returns the root when nothing else is hit.
+func (u *UI) WidgetAt(x, y float64) Widget {
+	items := u.painted()
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		if item.foreground {
+			if item.w.(Foreground).HitTestForeground(x, y) {
+				return item.w
+			}
+		} else if item.w.HitTest(x, y) {
+			return item.w
+		}
+	}
+	return u.root
+}
+
+// SetPointerCapture sends pointer events to w until the mouse button is released, e.g. while
+// dragging a slider thumb outside the slider.
+func (u *UI) SetPointerCapture(w Widget) {
+	u.capture = w
+}
+
+// ReleasePointerCapture ends a pointer capture early.
+func (u *UI) ReleasePointerCapture() {
+	u.capture = nil
+}
+
+// Hovered returns the widget under the cursor as of the last MouseMove, or nil.
+func (u *UI) Hovered() Widget {
+	return u.hover
+}
+
+// Dispatch delivers an input event through the tree (see Event). Pointer events go to the
+// pointer capture or the widget under the cursor; MouseMove also sends MouseLeave and
+// MouseEnter when the hovered widget changes. Keyboard events go to the open menu (context
+// menu or active menu bar), else the focused widget (or the root). Pressing a mouse
+// button closes open popups that do not contain the target; a press that closes one goes
+// no further, else it focuses the nearest focusable widget. Releasing it ends the pointer
+// capture. An unhandled right-click opens
+// the context menu of the target or its nearest ancestor with one (see SetContextMenu),
+// and so do the Menu key and Shift+F10 for the focused widget. An unhandled key press
+// runs its accelerator (see Accelerators, AddShortcut and the Shortcut field of menu
+// entries), and typed text is dropped after a key press an accelerator used; otherwise
+// Tab and Shift+Tab move focus, Escape closes all popups, and F10 or pressing and
+// releasing Alt alone activates the menu bar (see MenuBar).
+func (u *UI) Dispatch(e *Event) {
+	var target Widget
+	switch e.Type {
+	case EventKeyDown, EventKeyUp, EventTextInput:
+		target = u.focus
+		if u.menu != nil {
+			target = u.menu
+		}
+		if target == nil {
+			target = u.root
+		}
+	default:
+		hit := u.WidgetAt(e.X, e.Y)
+		if e.Type == EventMouseMove {
+			u.setHover(hit, e)
+		}
+		target = hit
+		if u.capture != nil {
+			target = u.capture
+		}
+	}
+
+	switch e.Type {
+	case EventMouseDown:
+		u.altTap = false
+		u.accel.pending = nil
+		// A press that dismisses a popup or menu goes no further, so it does not also
+		// press the widget underneath.
+		menuOutside := u.menu != nil && !u.menu.element().contains(target)
+		if u.closePopupsOutside(target) || menuOutside {
+			return
+		}
+		u.focusFromPointer(target)
+	case EventMouseUp:
+		u.capture = nil
+	case EventKeyDown:
+		if !e.Repeat {
+			u.altTap = isAltKey(e.Key)
+		}
+		u.accel.swallowText = false
+		// The strokes after the first of an accelerator sequence go to the registry first.
+		if len(u.accel.pending) > 0 && u.accel.handle(e, u.focus) {
+			return
+		}
+	case EventTextInput:
+		if u.accel.swallowText {
+			u.accel.swallowText = false
+			return
+		}
+	}
+	propagate(target, e)
+
+	if e.stopped {
 		return
 	}
-	if u.buttons[index].OnClick != nil {
-		u.buttons[index].OnClick()
+	switch e.Type {
+	case EventMouseDown:
+		if e.Button == ebiten.MouseButtonRight {
+			u.openContextMenu(target, e.X, e.Y)
+		}
+	case EventKeyDown:
+		switch {
+		case u.accel.handle(e, u.focus):
+		case e.Key == ebiten.KeyTab:
+			if e.Mods.Has(ModShift) {
+				u.FocusPrev()
+			} else {
+				u.FocusNext()
+			}
+		case e.Key == ebiten.KeyEscape:
+			u.closePopups()
+		case e.Key == ebiten.KeyContextMenu, e.Key == ebiten.KeyF10 && e.Mods.Has(ModShift):
+			if u.focus != nil {
+				b := u.focus.Container().Bounds
+				u.openContextMenu(u.focus, b.X, b.Y+b.H)
+			}
+		case e.Key == ebiten.KeyF10 && e.Mods == 0:
+			u.toggleMenuBar()
+		}
+	case EventKeyUp:
+		if e.Key == ebiten.KeyAlt && u.altTap {
+			u.altTap = false
+			u.toggleMenuBar()
+		}
 	}
 }
 
-// Checkboxes returns all checkboxes (for rendering and hit-test).
-func (u *UI) Checkboxes() []*Checkbox {
-	return u.checkboxes
+// isAltKey reports whether k is one of the Alt keys.
+func isAltKey(k ebiten.Key) bool {
+	return k == ebiten.KeyAlt || k == ebiten.KeyAltLeft || k == ebiten.KeyAltRight
 }
 
-// RadioGroups returns all radio groups (for rendering and hit-test).
-func (u *UI) RadioGroups() []*RadioGroup {
-	return u.radioGroups
+// toggleMenuBar deactivates the active menu bar, or activates the first menu bar in the
+// tree for keyboard navigation, closing other popups.
+func (u *UI) toggleMenuBar() {
+	if m, ok := u.menu.(*MenuBar); ok {
+		m.Close()
+		return
+	}
+	var bar *MenuBar
+	u.Walk(func(w Widget) bool {
+		if m, ok := w.(*MenuBar); ok && bar == nil {
+			bar = m
+		}
+		return bar == nil
+	})
+	if bar != nil {
+		u.closePopups()
+		bar.Activate()
+	}
 }
 
-// Sliders returns all sliders (for rendering and hit-test).
-func (u *UI) Sliders() []*Slider {
-	return u.sliders
+// openContextMenu opens the context menu of target or its nearest ancestor with one at
+// x, y, closing any other open popup.
+func (u *UI) openContextMenu(target Widget, x, y float64) {
+	for cur := target.element(); cur != nil; cur = cur.parent {
+		if cm := cur.contextMenu; cm != nil {
+			u.closePopups()
+			cm.Open(x, y)
+			return
+		}
+	}
 }
 
-// Dropdowns returns all dropdowns (for rendering and hit-test).
-func (u *UI) Dropdowns() []*Dropdown {
-	return u.dropdowns
+// setHover sends MouseLeave to the widgets the cursor left (innermost first) and
+// MouseEnter to the ones it entered (outermost first).
+func (u *UI) setHover(w Widget, move *Event) {
+	old := u.hover
+	if old == w {
+		return
+	}
+	u.hover = w
+	if old != nil {
+		for cur := old.element(); cur != nil; cur = cur.parent {
+			if !cur.contains(w) {
+				cur.deliver(&Event{Type: EventMouseLeave, X: move.X, Y: move.Y, Mods: move.Mods})
+			}
+		}
+	}
+	var entered []*Element
+	for cur := w.element(); cur != nil; cur = cur.parent {
+		if old == nil || !cur.contains(old) {
+			entered = append(entered, cur)
+		}
+	}
+	for i := len(entered) - 1; i >= 0; i-- {
+		entered[i].deliver(&Event{Type: EventMouseEnter, X: move.X, Y: move.Y, Mods: move.Mods})
+	}
 }
 
-// ContextMenus returns all context menus (for rendering and hit-test).
-func (u *UI) ContextMenus() []*ContextMenu {
-	return u.contextMenus
+// closePopupsOutside closes every open popup that is not target or one of its ancestors.
+// It reports whether it closed any.
+func (u *UI) closePopupsOutside(target Widget) bool {
+	closed := false
+	u.Walk(func(w Widget) bool {
+		if p, ok := w.(Popup); ok && p.IsOpen() && !w.element().contains(target) {
+			p.Close()
+			closed = true
+		}
+		return true
+	})
+	return closed
 }
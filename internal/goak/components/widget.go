@@ -0,0 +1,169 @@
+package components
+
+import (
+	"goak/internal/goak/layout"
+	"goak/internal/goak/rendering"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Widget is an element of the UI tree. The window lays out, draws, hit-tests and delivers
+// input to widgets by walking the tree, so custom widgets work like the built-in ones.
+// Implement a widget by embedding Element, which provides the tree links and defaults:
+//
+//	type Knob struct {
+//		components.Element
+//	}
+//
+//	k := &Knob{}
+//	k.Init(layout.NewContainer(layout.StaticPx(40), layout.StaticPx(40)))
+//	panel.AddChild(k)
+type Widget interface {
+	// Container returns the layout node of the widget.
+	Container() *layout.Container
+	// Draw paints the widget; its children are drawn after it.
+	Draw(ctx *DrawContext)
+	// HitTest reports whether the point (in layout coordinates) is on the widget.
+	HitTest(x, y float64) bool
+	// HandleEvent implements the widget's own reaction to input. It runs when the widget is
+	// the target and while events bubble up from its descendants, before listeners added
+	// with On. Call e.StopPropagation to keep the event from reaching ancestors.
+	HandleEvent(e *Event)
+
+	element() *Element
+}
+
+// Foreground is implemented by widgets that draw on top of their own children, like the
+// scrollbars of a ScrollPanel. It is drawn after the children and hit-tested before them.
+type Foreground interface {
+	DrawForeground(ctx *DrawContext)
+	HitTestForeground(x, y float64) bool
+}
+
+// Popup is implemented by widgets that open temporary overlays (dropdown lists, menus).
+// Open popups are closed when the mouse is pressed outside the popup and its children.
+type Popup interface {
+	IsOpen() bool
+	Close()
+}
+
+// DrawContext is passed to Widget.Draw. Dst is already clipped to the area the widget may
+// draw in; Face is the face of the UI default font and Theme the UI theme.
+type DrawContext struct {
+	Dst   *ebiten.Image
+	Face  text.Face
+	Theme *Theme
+
+	ui *UI
+}
+
+// FaceFor returns the face for a widget's Font field: Face when f is nil.
+func (ctx *DrawContext) FaceFor(f *Font) text.Face {
+	if f == nil {
+		return ctx.Face
+	}
+	return ctx.ui.faceFor(f)
+}
+
+// Element is the base of every widget: it holds the layout node and the links to the
+// parent and child widgets. Embed it in custom widgets and call Init.
+type Element struct {
+	ui          *UI
+	c           *layout.Container
+	self        Widget
+	parent      *Element
+	children    []Widget
+	z           int
+	listeners   []listener
+	focusable   bool
+	tabIndex    int
+	contextMenu *ContextMenu
+}
+
+// Init sets the layout node backing the element. Call it once when constructing a widget.
+func (e *Element) Init(c *layout.Container) {
+	e.c = c
+}
+
+func (e *Element) element() *Element { return e }
+
+// Container returns the layout node of the element.
+func (e *Element) Container() *layout.Container { return e.c }
+
+// Bounds returns the computed layout rect after Layout.
+func (e *Element) Bounds() layout.Rect { return e.c.Bounds }
+
+// UI returns the UI the element belongs to, or nil while it is not in a tree.
+func (e *Element) UI() *UI { return e.ui }
+
+// Parent returns the parent widget, or nil for the root and detached elements.
+func (e *Element) Parent() Widget {
+	if e.parent == nil {
+		return nil
+	}
+	return e.parent.self
+}
+
+// SetZIndex sets the paint order among siblings: children are painted in tree order,
+// except that a higher z-index paints later (on top) and is hit-tested first.
+// For floating elements it sets Floating.ZIndex, which orders them among all popups.
+func (e *Element) SetZIndex(z int) {
+	if e.c.Floating != nil {
+		e.c.Floating.ZIndex = z
+		return
+	}
+	e.z = z
+}
+
+// ZIndex returns the z-index set with SetZIndex (Floating.ZIndex for floating elements).
+func (e *Element) ZIndex() int {
+	if e.c.Floating != nil {
+		return e.c.Floating.ZIndex
+	}
+	return e.z
+}
+
+// Children returns the child widgets in tree order.
+func (e *Element) Children() []Widget { return e.children }
+
+// AddChild appends a widget as the last child, both in the widget tree and in layout.
+func (e *Element) AddChild(child Widget) {
+	ce := child.element()
+	ce.self = child
+	ce.parent = e
+	e.children = append(e.children, child)
+	e.c.Children = append(e.c.Children, ce.c)
+	if e.ui != nil {
+		ce.setUI(e.ui)
+	}
+}
+
+// setUI attaches the element and its subtree to u.
+func (e *Element) setUI(u *UI) {
+	e.ui = u
+	for _, child := range e.children {
+		child.element().setUI(u)
+	}
+}
+
+// contains reports whether w is this element or one of its descendants.
+func (e *Element) contains(w Widget) bool {
+	for cur := w.element(); cur != nil; cur = cur.parent {
+		if cur == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Draw draws nothing; widgets override it.
+func (e *Element) Draw(ctx *DrawContext) {}
+
+// HitTest reports whether the point is inside the element and not clipped by its ancestors.
+func (e *Element) HitTest(x, y float64) bool {
+	return rendering.PointWithinBounds(x, y, e.c.Bounds) && rendering.PointWithinBounds(x, y, e.c.Clip)
+}
+
+// HandleEvent ignores all events; widgets override it.
+func (e *Element) HandleEvent(ev *Event) {}
@@ -0,0 +1,66 @@
+package layout
+
+// axis selects the horizontal or vertical dimension during Pass 1.
+type axis int
+
+const (
+	axisX axis = iota
+	axisY
+)
+
+// isMainAxis reports whether a is the axis children are stacked along.
+func (c *Container) isMainAxis(a axis) bool {
+	return (c.Direction == LeftToRight) == (a == axisX)
+}
+
+func (c *Container) size(a axis) Size {
+	if a == axisX {
+		return c.Width
+	}
+	return c.Height
+}
+
+func (c *Container) extent(a axis) float64 {
+	if a == axisX {
+		return c.Bounds.W
+	}
+	return c.Bounds.H
+}
+
+func (c *Container) setExtent(a axis, v float64) {
+	if a == axisX {
+		c.Bounds.W = v
+	} else {
+		c.Bounds.H = v
+	}
+}
+
+func (c *Container) fit(a axis) float64 {
+	if a == axisX {
+		return c.fitW
+	}
+	return c.fitH
+}
+
+func (c *Container) setFit(a axis, v float64) {
+	if a == axisX {
+		c.fitW = v
+	} else {
+		c.fitH = v
+	}
+}
+
+// padding returns the total padding along a (both sides).
+func (c *Container) padding(a axis) float64 {
+	if a == axisX {
+		return c.Padding.Left + c.Padding.Right
+	}
+	return c.Padding.Top + c.Padding.Bottom
+}
+
+func (r Rect) extent(a axis) float64 {
+	if a == axisX {
+		return r.W
+	}
+	return r.H
+}
@@ -1,11 +1,23 @@
 // Package layout provides a two-pass layout system for nested containers.
 //
 // Sizing: each dimension (width/height) can be Static (fixed px), Percent (0–100 of parent),
-// or Auto (share remaining space with other Auto siblings).
+// Auto (share remaining space with other Auto siblings along the main axis, fill the cross axis)
+// or Fit (shrink-wrap the children, or the Measure result for leaves). Min/Max clamp any kind.
+//
+// Direction: a container stacks its children TopToBottom (default) or LeftToRight.
+// Padding insets the children from the container edges; ChildGap spaces consecutive children.
+// Overflow lets a container clip its children, or clip and scroll them by ScrollX/ScrollY;
+// Pass 2 records the Clip rect each node may draw in and each container's content extent.
+// Floating containers (tooltips, popups) leave the flow and attach to a point on their parent
+// or the viewport, with an offset, a z-index and optional clamping to the viewport.
+// Grid containers place children in column/row tracks (px, percent, fractional, fit) with
+// optional cell spans and per-cell alignment.
+// Wrap containers start a new line (spaced by LineGap) when children overflow the main axis;
+// each line is aligned on its own and a LeftToRight wrap container's Fit height covers every line.
 //
 // Two passes (similar to Clay):
-//   - Pass 1 (size): resolve each node's width and height from parent-available space.
-//   - Pass 2 (position): assign x,y; children are stacked vertically.
+//   - Pass 1 (size): per axis, compute Fit sizes bottom-up, then resolve sizes top-down.
+//   - Pass 2 (position): assign x,y; children are stacked along the container's Direction.
 //
 // Usage: build a tree with NewContainer, then call Layout(root, viewW, viewH) on init
 // and on every window resize. Each container's Bounds is filled with the computed Rect.
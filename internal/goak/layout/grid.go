@@ -0,0 +1,253 @@
+package layout
+
+import "slices"
+
+// Grid arranges a container's children in cells formed by column and row tracks instead of
+// stacking them. Tracks use the Size kinds: Static (px), Percent (of the content box),
+// Fit (largest single-span child in the track) and Auto, which acts as a fractional track
+// sharing the space left over by weight (Grow(2) takes twice the share of Grow(1)).
+// Children placed past the last row get extra Fit rows.
+type Grid struct {
+	Columns   []Size
+	Rows      []Size
+	ColumnGap float64
+	RowGap    float64
+}
+
+// GridCell places a child inside a Grid parent. Spans of 0 count as 1. Column and
+// ColumnSpan are clamped to the columns, Row and RowSpan to maxGridRows rows.
+type GridCell struct {
+	Column, Row         int
+	ColumnSpan, RowSpan int
+	HorizontalAlign     Alignment
+	VerticalAlign       Alignment
+}
+
+// EqualTracks returns n tracks that share the available space evenly.
+func EqualTracks(n int) []Size {
+	tracks := make([]Size, n)
+	for i := range tracks {
+		tracks[i] = Grow(1)
+	}
+	return tracks
+}
+
+// maxGridRows bounds the rows a Grid can have, so a large GridCell.Row or RowSpan does not
+// create tracks without end.
+const maxGridRows = 1024
+
+// gridLayout holds the cells of a Grid container's children, resolved once per Layout.
+type gridLayout struct {
+	placements []gridPlacement
+	rows       int
+	taken      map[[2]int]bool
+}
+
+// gridPlacement is a child's resolved cell in track indices.
+type gridPlacement struct {
+	child      *Container
+	col, row   int
+	cols, rows int
+	hAlign     Alignment
+	vAlign     Alignment
+}
+
+// span returns the first track and track count of p along a.
+func (p gridPlacement) span(a axis) (start, n int) {
+	if a == axisX {
+		return p.col, p.cols
+	}
+	return p.row, p.rows
+}
+
+// placeGrid resolves every flow child of a Grid container to a cell. Children with a Cell
+// keep it (clamped to the columns and maxGridRows); the others fill the free cells row by
+// row. Pass 1 calls it once per Layout and the rest of the layout reuses c.grid.
+func placeGrid(c *Container) {
+	g := c.Grid
+	cols := max(len(g.Columns), 1)
+	gl := &c.grid
+	gl.rows = len(g.Rows)
+	if gl.taken == nil {
+		gl.taken = make(map[[2]int]bool)
+	}
+	clear(gl.taken)
+	occupy := func(p gridPlacement) {
+		for r := p.row; r < p.row+p.rows; r++ {
+			for col := p.col; col < p.col+p.cols; col++ {
+				gl.taken[[2]int{col, r}] = true
+			}
+		}
+		gl.rows = max(gl.rows, p.row+p.rows)
+	}
+
+	flow := c.flowChildren()
+	gl.placements = slices.Grow(gl.placements[:0], len(flow))[:len(flow)]
+	for i, child := range flow {
+		cell := child.Cell
+		if cell == nil {
+			continue
+		}
+		p := gridPlacement{
+			child:  child,
+			col:    min(max(cell.Column, 0), cols-1),
+			row:    min(max(cell.Row, 0), maxGridRows-1),
+			cols:   max(cell.ColumnSpan, 1),
+			rows:   max(cell.RowSpan, 1),
+			hAlign: cell.HorizontalAlign,
+			vAlign: cell.VerticalAlign,
+		}
+		p.cols = min(p.cols, cols-p.col)
+		p.rows = min(p.rows, maxGridRows-p.row)
+		gl.placements[i] = p
+		occupy(p)
+	}
+
+	next := 0
+	for i, child := range flow {
+		if child.Cell != nil {
+			continue
+		}
+		for gl.taken[[2]int{next % cols, next / cols}] {
+			next++
+		}
+		p := gridPlacement{
+			child:  child,
+			col:    next % cols,
+			row:    next / cols,
+			cols:   1,
+			rows:   1,
+			hAlign: c.HorizontalAlign,
+			vAlign: c.VerticalAlign,
+		}
+		gl.placements[i] = p
+		occupy(p)
+		next++
+	}
+}
+
+// tracks returns the track specs along a, padded with Fit tracks up to count.
+func (g *Grid) tracks(a axis, count int) []Size {
+	specs := g.Columns
+	if a == axisY {
+		specs = g.Rows
+	}
+	if len(specs) >= count {
+		return specs
+	}
+	out := make([]Size, count)
+	copy(out, specs)
+	for i := len(specs); i < count; i++ {
+		out[i] = FitSize()
+	}
+	return out
+}
+
+func (g *Grid) gap(a axis) float64 {
+	if a == axisX {
+		return g.ColumnGap
+	}
+	return g.RowGap
+}
+
+// trackCount returns the number of tracks along a, including implicit rows.
+func trackCount(c *Container, a axis, rowCount int) int {
+	if a == axisX {
+		return max(len(c.Grid.Columns), 1)
+	}
+	return rowCount
+}
+
+// trackContentSizes returns, per track, the largest fit contribution of single-span children.
+func trackContentSizes(placements []gridPlacement, a axis, n int) []float64 {
+	sizes := make([]float64, n)
+	for _, p := range placements {
+		start, span := p.span(a)
+		if span == 1 && start < n {
+			sizes[start] = max(sizes[start], p.child.fitContribution(a))
+		}
+	}
+	return sizes
+}
+
+// gridFit returns the content size of a grid along a: every track at its content size.
+func gridFit(c *Container, a axis) float64 {
+	n := trackCount(c, a, c.grid.rows)
+	specs := c.Grid.tracks(a, n)
+	content := trackContentSizes(c.grid.placements, a, n)
+	total := c.Grid.gap(a) * float64(max(n-1, 0))
+	for i, s := range specs {
+		if s.Kind == Static {
+			total += s.clamp(s.Value)
+		} else {
+			total += s.clamp(content[i])
+		}
+	}
+	return total
+}
+
+// gridTrackSizes resolves track sizes along a for a content box of the given size.
+func gridTrackSizes(c *Container, a axis, content float64) []float64 {
+	n := trackCount(c, a, c.grid.rows)
+	specs := c.Grid.tracks(a, n)
+	fits := trackContentSizes(c.grid.placements, a, n)
+	sizes := make([]float64, n)
+	free := content - c.Grid.gap(a)*float64(max(n-1, 0))
+	var totalWeight float64
+	for i, s := range specs {
+		if s.Kind == Auto {
+			totalWeight += s.growWeight()
+			continue
+		}
+		sizes[i] = resolveSize(s, content, fits[i])
+		free -= sizes[i]
+	}
+	free = nonNegative(free)
+	for i, s := range specs {
+		if s.Kind == Auto {
+			sizes[i] = s.clamp(free * s.growWeight() / totalWeight)
+		}
+	}
+	return sizes
+}
+
+// cellSpan returns the offset and extent of tracks [start, start+n) including inner gaps.
+func cellSpan(sizes []float64, gap float64, start, n int) (offset, extent float64) {
+	for i := 0; i < start && i < len(sizes); i++ {
+		offset += sizes[i] + gap
+	}
+	for i := start; i < start+n && i < len(sizes); i++ {
+		extent += sizes[i]
+	}
+	return offset, extent + gap*float64(max(n-1, 0))
+}
+
+// sizeGridAxis sizes grid children along a: Auto children fill their cell.
+func sizeGridAxis(c *Container, a axis, content float64) {
+	sizes := gridTrackSizes(c, a, content)
+	for _, p := range c.grid.placements {
+		start, span := p.span(a)
+		_, extent := cellSpan(sizes, c.Grid.gap(a), start, span)
+		p.child.setExtent(a, resolveSize(p.child.size(a), extent, p.child.fit(a)))
+	}
+}
+
+// positionGrid places grid children inside their cells, aligned per cell.
+func positionGrid(c *Container, clip, view Rect) {
+	contentW := nonNegative(c.Bounds.W - c.padding(axisX))
+	contentH := nonNegative(c.Bounds.H - c.padding(axisY))
+	cols := gridTrackSizes(c, axisX, contentW)
+	rows := gridTrackSizes(c, axisY, contentH)
+
+	_, extentW := cellSpan(cols, c.Grid.ColumnGap, 0, len(cols))
+	_, extentH := cellSpan(rows, c.Grid.RowGap, 0, len(rows))
+	ox, oy, childClip := c.contentOrigin(extentW, extentH, clip)
+
+	for _, p := range c.grid.placements {
+		cx, cw := cellSpan(cols, c.Grid.ColumnGap, p.col, p.cols)
+		cy, ch := cellSpan(rows, c.Grid.RowGap, p.row, p.rows)
+		x := ox + cx + AlignOffset(p.hAlign, cw, p.child.Bounds.W)
+		y := oy + cy + AlignOffset(p.vAlign, ch, p.child.Bounds.H)
+		pass2Position(p.child, x, y, childClip, view)
+	}
+}
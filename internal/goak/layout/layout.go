@@ -4,112 +4,410 @@ package layout
 // Layout runs the two-pass layout: Pass 1 resolves sizes, Pass 2 assigns positions.
 // root.Bounds is set to (0, 0, viewW, viewH). Call on window resize with new viewW, viewH.
 func Layout(root *Container, viewW, viewH float64) {
-	pass1Size(root, viewW, viewH)
-	pass2Position(root, 0, 0)
+	view := Rect{W: viewW, H: viewH}
+	pass1Size(root, view)
+	pass2Position(root, 0, 0, view, view)
 }
 
-// pass1Size (Pass 1): resolve each node's width and height from parent-available space.
+// pass1Size (Pass 1): resolve every node's width and height. Widths are resolved first so
+// that heights of wrapped content can depend on them; each axis runs two sub-passes:
+//   - fit (bottom-up): content size of every node from its children or Measure.
+//   - grow (top-down): each node hands its content box (bounds minus Padding) to its children.
+//
 // Fills Bounds.W and Bounds.H only.
-func pass1Size(c *Container, availW, availH float64) {
-	w := resolveSize(c.Width, availW)
-	h := resolveSize(c.Height, availH)
-	c.Bounds.W = w
-	c.Bounds.H = h
-
-	contentW := w
-	contentH := h
+func pass1Size(root *Container, view Rect) {
+	fitAxis(root, axisX)
+	root.Bounds.W = resolveSize(root.Width, view.W, root.fitW)
+	sizeAxis(root, axisX, view)
+
+	fitAxis(root, axisY)
+	root.Bounds.H = resolveSize(root.Height, view.H, root.fitH)
+	sizeAxis(root, axisY, view)
+}
+
+// fitAxis computes the Fit content size of c and its subtree along one axis.
+// Along the main axis children add up (plus ChildGap); along the cross axis the largest wins.
+// Floating children do not count towards their parent's content. A LeftToRight Wrap
+// container's height covers all of its lines.
+func fitAxis(c *Container, a axis) {
+	for _, child := range c.Children {
+		fitAxis(child, a)
+	}
+	if c.Grid != nil && a == axisX {
+		placeGrid(c)
+	}
+
+	flow := c.flowChildren()
+	var content float64
+	switch {
+	case len(flow) == 0:
+		if c.Measure != nil {
+			w, h := c.Measure(measureWidth(c, a))
+			content = w
+			if a == axisY {
+				content = h
+			}
+		}
+	case c.Grid != nil:
+		content = gridFit(c, a)
+	case c.Wrap && c.Direction == LeftToRight && a == axisY:
+		content = wrapFitHeight(c)
+	case c.isMainAxis(a):
+		content = c.totalGap()
+		for _, child := range flow {
+			content += child.fitContribution(a)
+		}
+	default:
+		for _, child := range flow {
+			content = max(content, child.fitContribution(a))
+		}
+	}
+	c.setFit(a, content+c.padding(a))
+}
+
+// measureWidth is the width passed to Measure: unknown (0) while fitting widths,
+// the resolved width once heights are computed.
+func measureWidth(c *Container, a axis) float64 {
+	if a == axisX {
+		return 0
+	}
+	return c.Bounds.W
+}
+
+// fitContribution is how much of a Fit parent's content a child claims along an axis.
+// Static children claim their size; all other kinds claim their own content size.
+func (c *Container) fitContribution(a axis) float64 {
+	s := c.size(a)
+	if s.Kind == Static {
+		return s.clamp(s.Value)
+	}
+	return s.clamp(c.fit(a))
+}
+
+// sizeAxis resolves the children of c along one axis; c's own extent is already set.
+// Along the main axis Auto children share the space left by their siblings and ChildGap
+// in proportion to their Weight; along the cross axis Auto children fill the content box.
+// Grid containers size children to their cells and Wrap containers per line; floating
+// children resolve against their attach target instead.
+func sizeAxis(c *Container, a axis, view Rect) {
 	if len(c.Children) == 0 {
 		return
 	}
+	content := nonNegative(c.extent(a) - c.padding(a))
+
+	flow := c.flowChildren()
+	switch {
+	case c.Grid != nil:
+		sizeGridAxis(c, a, content)
+	case c.Wrap && c.isMainAxis(a):
+		sizeWrapMain(c, a, content)
+	case c.Wrap:
+		sizeWrapCross(c, a, content)
+	case c.isMainAxis(a):
+		distribute(flow, a, content, content-c.totalGap(), c.Overflow != OverflowScroll)
+	default:
+		for _, child := range flow {
+			child.setExtent(a, resolveSize(child.size(a), content, child.fit(a)))
+		}
+	}
 
-	// Fixed/percent children claim space; Auto children share remaining.
-	var autoCountW, autoCountH int
-	var fixedW, fixedH float64
 	for _, child := range c.Children {
-		if child.Width.Kind == Auto {
-			autoCountW++
-		} else {
-			fixedW += resolveSize(child.Width, contentW)
+		if child.Floating != nil {
+			target := c.extent(a)
+			if child.Floating.AttachTo == AttachToViewport {
+				target = view.extent(a)
+			}
+			child.setExtent(a, resolveSize(child.size(a), target, child.fit(a)))
 		}
-		if child.Height.Kind == Auto {
-			autoCountH++
+		sizeAxis(child, a, view)
+	}
+}
+
+// distribute sets the main-axis extent of children sharing space (content minus gaps).
+// Non-Auto children get their size resolved against content; Auto children split what is
+// left by weight. When the non-Auto children alone overflow space, they shrink in
+// proportion to their size instead (unless canShrink is false, as in scroll containers).
+// Children that hit Min or Max are frozen and the rest is redistributed.
+func distribute(children []*Container, a axis, content, space float64, canShrink bool) {
+	sizes := make([]float64, len(children))
+	frozen := make([]bool, len(children))
+	used := 0.0
+	for i, child := range children {
+		s := child.size(a)
+		if s.Kind == Auto {
+			sizes[i] = s.clamp(0)
 		} else {
-			fixedH += resolveSize(child.Height, contentH)
+			sizes[i] = resolveSize(s, content, child.fit(a))
+			frozen[i] = true
 		}
+		used += sizes[i]
 	}
-	remainingW := contentW - fixedW
-	if remainingW < 0 {
-		remainingW = 0
-	}
-	remainingH := contentH - fixedH
-	if remainingH < 0 {
-		remainingH = 0
+
+	if used > space {
+		if canShrink {
+			shrink(children, a, sizes, used-space)
+		}
+	} else {
+		grow(children, a, sizes, frozen, space-used)
 	}
-	childW := remainingW
-	childH := remainingH
-	if autoCountW > 0 {
-		childW = remainingW / float64(autoCountW)
+	for i, child := range children {
+		child.setExtent(a, sizes[i])
 	}
-	if autoCountH > 0 {
-		childH = remainingH / float64(autoCountH)
+}
+
+// grow hands free space to unfrozen (Auto) children by weight, respecting Max.
+func grow(children []*Container, a axis, sizes []float64, frozen []bool, free float64) {
+	for free > epsilon {
+		var totalWeight float64
+		for i, child := range children {
+			if !frozen[i] {
+				totalWeight += child.size(a).growWeight()
+			}
+		}
+		if totalWeight == 0 {
+			return
+		}
+		next := free
+		clamped := false
+		for i, child := range children {
+			if frozen[i] {
+				continue
+			}
+			s := child.size(a)
+			want := sizes[i] + free*s.growWeight()/totalWeight
+			got := s.clamp(want)
+			if got != want {
+				frozen[i] = true
+				clamped = true
+			}
+			next -= got - sizes[i]
+			sizes[i] = got
+		}
+		free = next
+		if !clamped {
+			return
+		}
 	}
+}
 
-	for _, child := range c.Children {
-		cw := resolveSize(child.Width, contentW)
-		ch := resolveSize(child.Height, contentH)
-		if child.Width.Kind == Auto {
-			cw = childW
+// shrink takes overflow away from children in proportion to their size, respecting Min.
+func shrink(children []*Container, a axis, sizes []float64, overflow float64) {
+	for overflow > epsilon {
+		var total float64
+		for i, child := range children {
+			if sizes[i] > child.size(a).Min {
+				total += sizes[i]
+			}
+		}
+		if total == 0 {
+			return
 		}
-		if child.Height.Kind == Auto {
-			ch = childH
+		next := overflow
+		for i, child := range children {
+			minSize := child.size(a).Min
+			if sizes[i] <= minSize {
+				continue
+			}
+			got := max(sizes[i]-overflow*sizes[i]/total, minSize)
+			next -= sizes[i] - got
+			sizes[i] = got
 		}
-		pass1Size(child, cw, ch)
+		overflow = next
 	}
 }
 
-func resolveSize(s Size, parent float64) float64 {
+// epsilon is the leftover space below which distribution stops.
+const epsilon = 0.01
+
+// resolveSize returns the size in pixels for s given the parent's available space and the
+// node's own fit content size, clamped to s.Min/s.Max.
+func resolveSize(s Size, parent, fit float64) float64 {
 	switch s.Kind {
 	case Static:
-		return s.Value
+		return s.clamp(s.Value)
 	case Percent:
-		return parent * (s.Value / 100)
-	case Auto:
-		return parent
+		return s.clamp(parent * (s.Value / 100))
+	case Fit:
+		return s.clamp(fit)
 	default:
-		return parent
+		return s.clamp(parent)
 	}
 }
 
-// pass2Position (Pass 2): assign x,y to each node. Children stacked vertically.
-// Fills Bounds.X and Bounds.Y.
-func pass2Position(c *Container, x, y float64) {
+// pass2Position (Pass 2): assign x,y to each node. Children are stacked along the
+// container's Direction, shifted by the scroll offset for OverflowScroll.
+// clip is the drawable area inherited from ancestors; containers that clip narrow it to
+// their bounds for their children. Floating children are placed last, against their attach
+// target, and may draw anywhere in the viewport.
+// Fills Bounds.X, Bounds.Y, Clip and ContentW/ContentH.
+func pass2Position(c *Container, x, y float64, clip, view Rect) {
 	c.Bounds.X = x
 	c.Bounds.Y = y
+	c.Clip = clip
+
+	switch {
+	case c.Grid != nil:
+		positionGrid(c, clip, view)
+	case c.Wrap:
+		positionWrap(c, clip, view)
+	default:
+		positionStack(c, clip, view)
+	}
 
-	var totalChildH float64
 	for _, child := range c.Children {
-		totalChildH += child.Bounds.H
+		if child.Floating != nil {
+			positionFloating(child, c.Bounds, view)
+		}
+	}
+}
+
+// positionStack places the flow children one after another along the main axis.
+func positionStack(c *Container, clip, view Rect) {
+	horizontal := c.Direction == LeftToRight
+	contentW := c.Bounds.W - c.Padding.Left - c.Padding.Right
+	contentH := c.Bounds.H - c.Padding.Top - c.Padding.Bottom
+	mainAlign, crossAlign := c.VerticalAlign, c.HorizontalAlign
+	boxMain, boxCross := contentH, contentW
+	if horizontal {
+		mainAlign, crossAlign = c.HorizontalAlign, c.VerticalAlign
+		boxMain, boxCross = contentW, contentH
 	}
 
-	cy := y
-	switch c.VerticalAlign {
+	flow := c.flowChildren()
+	totalMain := c.totalGap()
+	var maxCross float64
+	for _, child := range flow {
+		totalMain += child.mainExtent(horizontal)
+		maxCross = max(maxCross, child.crossExtent(horizontal))
+	}
+	extentW, extentH := maxCross, totalMain
+	if horizontal {
+		extentW, extentH = totalMain, maxCross
+	}
+	ox, oy, childClip := c.contentOrigin(extentW, extentH, clip)
+	// Scrolled content starts at the edge; alignment only applies when it fits.
+	if c.Overflow == OverflowScroll && totalMain > boxMain {
+		mainAlign = AlignStart
+	}
+
+	cursor := AlignOffset(mainAlign, boxMain, totalMain)
+	for _, child := range flow {
+		cross := AlignOffset(crossAlign, boxCross, child.crossExtent(horizontal))
+		cx, cy := ox+cross, oy+cursor
+		if horizontal {
+			cx, cy = ox+cursor, oy+cross
+		}
+		pass2Position(child, cx, cy, childClip, view)
+		cursor += child.mainExtent(horizontal) + c.ChildGap
+	}
+}
+
+// contentOrigin records ContentW/ContentH from the children's extent (padding excluded),
+// applies Overflow and returns the origin of the (scrolled) content box and the clip rect
+// for the children.
+func (c *Container) contentOrigin(extentW, extentH float64, clip Rect) (ox, oy float64, childClip Rect) {
+	c.ContentW = extentW + c.padding(axisX)
+	c.ContentH = extentH + c.padding(axisY)
+	ox = c.Bounds.X + c.Padding.Left
+	oy = c.Bounds.Y + c.Padding.Top
+	childClip = clip
+	switch c.Overflow {
+	case OverflowScroll:
+		c.ScrollX = clampScroll(c.ScrollX, c.ContentW, c.Bounds.W)
+		c.ScrollY = clampScroll(c.ScrollY, c.ContentH, c.Bounds.H)
+		ox -= c.ScrollX
+		oy -= c.ScrollY
+		childClip = c.Bounds.Intersect(clip)
+	case OverflowClip:
+		childClip = c.Bounds.Intersect(clip)
+	}
+	return ox, oy, childClip
+}
+
+// positionFloating places a floating container against its parent's bounds or the viewport.
+func positionFloating(c *Container, parent, view Rect) {
+	f := c.Floating
+	target := parent
+	if f.AttachTo == AttachToViewport {
+		target = view
+	}
+	tx, ty := target.point(f.Target)
+	ex, ey := Rect{W: c.Bounds.W, H: c.Bounds.H}.point(f.Element)
+	x := tx - ex + f.OffsetX
+	y := ty - ey + f.OffsetY
+	if f.FlipX && x+c.Bounds.W > view.X+view.W {
+		tx, _ = target.point(f.Target.mirrorX())
+		ex, _ = Rect{W: c.Bounds.W, H: c.Bounds.H}.point(f.Element.mirrorX())
+		x = tx - ex - f.OffsetX
+	}
+	if f.ClampToViewport {
+		x = max(view.X, min(x, view.X+view.W-c.Bounds.W))
+		y = max(view.Y, min(y, view.Y+view.H-c.Bounds.H))
+	}
+	pass2Position(c, x, y, view, view)
+}
+
+// clampScroll keeps a scroll offset within [0, content-view].
+func clampScroll(offset, content, view float64) float64 {
+	return max(0, min(offset, content-view))
+}
+
+// flowChildren returns the children that take part in stacking (all but floating ones).
+func (c *Container) flowChildren() []*Container {
+	for i, child := range c.Children {
+		if child.Floating == nil {
+			continue
+		}
+		flow := make([]*Container, i, len(c.Children)-1)
+		copy(flow, c.Children[:i])
+		for _, rest := range c.Children[i+1:] {
+			if rest.Floating == nil {
+				flow = append(flow, rest)
+			}
+		}
+		return flow
+	}
+	return c.Children
+}
+
+// totalGap returns the space taken by ChildGap between all stacked children.
+func (c *Container) totalGap() float64 {
+	n := len(c.flowChildren())
+	if n < 2 {
+		return 0
+	}
+	return c.ChildGap * float64(n-1)
+}
+
+func nonNegative(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
+
+// AlignOffset returns where an item of the given extent starts inside a box.
+func AlignOffset(a Alignment, box, extent float64) float64 {
+	switch a {
 	case AlignCenter:
-		cy = y + (c.Bounds.H-totalChildH)/2
+		return (box - extent) / 2
 	case AlignEnd:
-		cy = y + (c.Bounds.H - totalChildH)
+		return box - extent
+	default:
+		return 0
 	}
+}
 
-	for _, child := range c.Children {
-		cx := x
-		switch c.HorizontalAlign {
-		case AlignCenter:
-			cx = x + (c.Bounds.W-child.Bounds.W)/2
-		case AlignEnd:
-			cx = x + (c.Bounds.W - child.Bounds.W)
-		}
-		child.Bounds.X = cx
-		child.Bounds.Y = cy
-		pass2Position(child, cx, cy)
-		cy += child.Bounds.H
+func (c *Container) mainExtent(horizontal bool) float64 {
+	if horizontal {
+		return c.Bounds.W
+	}
+	return c.Bounds.H
+}
+
+func (c *Container) crossExtent(horizontal bool) float64 {
+	if horizontal {
+		return c.Bounds.H
 	}
+	return c.Bounds.W
 }
@@ -0,0 +1,345 @@
+package layout
+
+import (
+	"math"
+	"testing"
+)
+
+// layoutCase lays out the tree build returns in a viewW x viewH viewport and checks the
+// bounds of the returned nodes against want, in order.
+type layoutCase struct {
+	name         string
+	viewW, viewH float64
+	build        func() (root *Container, nodes []*Container)
+	want         []Rect
+}
+
+func runLayoutCases(t *testing.T, cases []layoutCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, nodes := tc.build()
+			Layout(root, tc.viewW, tc.viewH)
+			if len(nodes) != len(tc.want) {
+				t.Fatalf("got %d nodes, want %d", len(nodes), len(tc.want))
+			}
+			for i, n := range nodes {
+				if !rectNear(n.Bounds, tc.want[i]) {
+					t.Errorf("node %d: bounds %+v, want %+v", i, n.Bounds, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func rectNear(a, b Rect) bool {
+	const tolerance = 0.05
+	return math.Abs(a.X-b.X) < tolerance && math.Abs(a.Y-b.Y) < tolerance &&
+		math.Abs(a.W-b.W) < tolerance && math.Abs(a.H-b.H) < tolerance
+}
+
+// row returns a LeftToRight container of the given size holding children.
+func row(width, height Size, children ...*Container) *Container {
+	c := NewContainer(width, height, children...)
+	c.Direction = LeftToRight
+	return c
+}
+
+func TestDistribute(t *testing.T) {
+	runLayoutCases(t, []layoutCase{
+		{
+			name:  "grow weights share the free space",
+			viewW: 300, viewH: 100,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(Grow(1), AutoSize())
+				b := NewContainer(Grow(2), AutoSize())
+				return row(AutoSize(), AutoSize(), a, b), []*Container{a, b}
+			},
+			want: []Rect{{0, 0, 100, 100}, {100, 0, 200, 100}},
+		},
+		{
+			name:  "grow stops at Max and hands the rest on",
+			viewW: 300, viewH: 100,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(Grow(1).WithMax(50), AutoSize())
+				b := NewContainer(Grow(1), AutoSize())
+				return row(AutoSize(), AutoSize(), a, b), []*Container{a, b}
+			},
+			want: []Rect{{0, 0, 50, 100}, {50, 0, 250, 100}},
+		},
+		{
+			name:  "grow shares what static siblings leave",
+			viewW: 300, viewH: 100,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(StaticPx(100), AutoSize())
+				b := NewContainer(AutoSize(), AutoSize())
+				c := NewContainer(PercentOf(10), AutoSize())
+				return row(AutoSize(), AutoSize(), a, b, c), []*Container{a, b, c}
+			},
+			want: []Rect{{0, 0, 100, 100}, {100, 0, 170, 100}, {270, 0, 30, 100}},
+		},
+		{
+			name:  "overflowing children shrink in proportion",
+			viewW: 300, viewH: 100,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(StaticPx(300), AutoSize())
+				b := NewContainer(StaticPx(100), AutoSize())
+				return row(AutoSize(), AutoSize(), a, b), []*Container{a, b}
+			},
+			want: []Rect{{0, 0, 225, 100}, {225, 0, 75, 100}},
+		},
+		{
+			name:  "shrink stops at Min and takes the rest from siblings",
+			viewW: 300, viewH: 100,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(StaticPx(200).WithMin(180), AutoSize())
+				b := NewContainer(StaticPx(200), AutoSize())
+				return row(AutoSize(), AutoSize(), a, b), []*Container{a, b}
+			},
+			want: []Rect{{0, 0, 180, 100}, {180, 0, 120, 100}},
+		},
+		{
+			name:  "scroll containers do not shrink their children",
+			viewW: 300, viewH: 100,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(StaticPx(200), AutoSize())
+				b := NewContainer(StaticPx(200), AutoSize())
+				r := row(AutoSize(), AutoSize(), a, b)
+				r.Overflow = OverflowScroll
+				return r, []*Container{a, b}
+			},
+			want: []Rect{{0, 0, 200, 100}, {200, 0, 200, 100}},
+		},
+	})
+}
+
+func TestPaddingAndChildGap(t *testing.T) {
+	runLayoutCases(t, []layoutCase{
+		{
+			name:  "top to bottom",
+			viewW: 200, viewH: 200,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(AutoSize(), StaticPx(50))
+				b := NewContainer(AutoSize(), AutoSize())
+				root := NewContainer(AutoSize(), AutoSize(), a, b)
+				root.Padding = Padding{Left: 10, Top: 20, Right: 30, Bottom: 40}
+				root.ChildGap = 5
+				return root, []*Container{a, b}
+			},
+			want: []Rect{{10, 20, 160, 50}, {10, 75, 160, 85}},
+		},
+		{
+			name:  "left to right, centered",
+			viewW: 200, viewH: 100,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(StaticPx(40), StaticPx(20))
+				b := NewContainer(StaticPx(60), StaticPx(40))
+				root := row(AutoSize(), AutoSize(), a, b)
+				root.Padding = PaddingXY(10, 5)
+				root.ChildGap = 20
+				root.HorizontalAlign = AlignCenter
+				root.VerticalAlign = AlignCenter
+				return root, []*Container{a, b}
+			},
+			want: []Rect{{40, 40, 40, 20}, {100, 30, 60, 40}},
+		},
+	})
+}
+
+func TestFit(t *testing.T) {
+	runLayoutCases(t, []layoutCase{
+		{
+			name:  "fit wraps measured and static children",
+			viewW: 400, viewH: 400,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(StaticPx(30), StaticPx(10))
+				leaf := NewContainer(FitSize(), FitSize())
+				leaf.Measure = func(float64) (float64, float64) { return 20, 15 }
+				fit := row(FitSize(), FitSize(), a, leaf)
+				fit.Padding = PaddingAll(5)
+				fit.ChildGap = 4
+				return NewContainer(AutoSize(), AutoSize(), fit), []*Container{fit, a, leaf}
+			},
+			want: []Rect{{0, 0, 64, 25}, {5, 5, 30, 10}, {39, 5, 20, 15}},
+		},
+		{
+			name:  "fit is clamped by Min and Max",
+			viewW: 400, viewH: 400,
+			build: func() (*Container, []*Container) {
+				wide := NewContainer(FitSize().WithMax(50), FitSize().WithMin(30),
+					NewContainer(StaticPx(80), StaticPx(10)))
+				return NewContainer(AutoSize(), AutoSize(), wide), []*Container{wide}
+			},
+			want: []Rect{{0, 0, 50, 30}},
+		},
+		{
+			name:  "measure gets the resolved width for the height",
+			viewW: 400, viewH: 400,
+			build: func() (*Container, []*Container) {
+				text := NewContainer(StaticPx(100), FitSize())
+				text.Measure = func(availW float64) (float64, float64) {
+					if availW == 0 {
+						return 300, 10
+					}
+					return availW, 10 * math.Ceil(300/availW)
+				}
+				return NewContainer(AutoSize(), AutoSize(), text), []*Container{text}
+			},
+			want: []Rect{{0, 0, 100, 30}},
+		},
+	})
+}
+
+func TestWrap(t *testing.T) {
+	runLayoutCases(t, []layoutCase{
+		{
+			name:  "lines break at the width and stack with LineGap",
+			viewW: 400, viewH: 400,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(StaticPx(40), StaticPx(20))
+				b := NewContainer(StaticPx(40), StaticPx(30))
+				c := NewContainer(StaticPx(40), StaticPx(20))
+				d := NewContainer(StaticPx(40), StaticPx(20))
+				w := row(StaticPx(100), FitSize(), a, b, c, d)
+				w.Wrap = true
+				w.ChildGap = 10
+				w.LineGap = 5
+				return NewContainer(AutoSize(), AutoSize(), w), []*Container{w, a, b, c, d}
+			},
+			want: []Rect{{0, 0, 100, 55}, {0, 0, 40, 20}, {50, 0, 40, 30}, {0, 35, 40, 20}, {50, 35, 40, 20}},
+		},
+		{
+			name:  "each line is aligned on its own",
+			viewW: 400, viewH: 400,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(StaticPx(60), StaticPx(10))
+				b := NewContainer(StaticPx(60), StaticPx(10))
+				c := NewContainer(StaticPx(20), StaticPx(10))
+				w := row(StaticPx(100), FitSize(), a, b, c)
+				w.Wrap = true
+				w.ChildGap = 10
+				w.LineGap = 2
+				w.HorizontalAlign = AlignEnd
+				return NewContainer(AutoSize(), AutoSize(), w), []*Container{a, b, c}
+			},
+			want: []Rect{{40, 0, 60, 10}, {10, 12, 60, 10}, {80, 12, 20, 10}},
+		},
+		{
+			name:  "auto children grow into the rest of their line",
+			viewW: 400, viewH: 400,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(StaticPx(70), StaticPx(10))
+				b := NewContainer(AutoSize(), StaticPx(10), NewContainer(StaticPx(20), StaticPx(10)))
+				w := row(StaticPx(100), FitSize(), a, b)
+				w.Wrap = true
+				w.ChildGap = 10
+				return NewContainer(AutoSize(), AutoSize(), w), []*Container{a, b}
+			},
+			want: []Rect{{0, 0, 70, 10}, {80, 0, 20, 10}},
+		},
+	})
+}
+
+func TestGrid(t *testing.T) {
+	runLayoutCases(t, []layoutCase{
+		{
+			name:  "auto placement fills around spanning cells",
+			viewW: 400, viewH: 400,
+			build: func() (*Container, []*Container) {
+				a := NewContainer(AutoSize(), StaticPx(20))
+				b := NewContainer(AutoSize(), StaticPx(30))
+				b.Cell = &GridCell{Column: 0, Row: 1, ColumnSpan: 2}
+				c := NewContainer(AutoSize(), StaticPx(20))
+				d := NewContainer(StaticPx(10), StaticPx(10))
+				g := NewContainer(StaticPx(200), FitSize(), a, b, c, d)
+				g.Grid = &Grid{Columns: []Size{StaticPx(50), Grow(1)}, ColumnGap: 10, RowGap: 5}
+				return NewContainer(AutoSize(), AutoSize(), g), []*Container{g, a, c, b, d}
+			},
+			want: []Rect{
+				{0, 0, 200, 70},
+				{0, 0, 50, 20},
+				{60, 0, 140, 20},
+				{0, 25, 200, 30},
+				{0, 60, 10, 10},
+			},
+		},
+		{
+			name:  "row spans and per-cell alignment",
+			viewW: 400, viewH: 400,
+			build: func() (*Container, []*Container) {
+				tall := NewContainer(StaticPx(20), StaticPx(20))
+				tall.Cell = &GridCell{Column: 1, RowSpan: 2, HorizontalAlign: AlignCenter, VerticalAlign: AlignEnd}
+				a := NewContainer(AutoSize(), AutoSize())
+				b := NewContainer(AutoSize(), AutoSize())
+				g := NewContainer(StaticPx(100), StaticPx(100), tall, a, b)
+				g.Grid = &Grid{Columns: EqualTracks(2), Rows: []Size{StaticPx(30), Grow(1)}}
+				return NewContainer(AutoSize(), AutoSize(), g), []*Container{tall, a, b}
+			},
+			want: []Rect{{65, 80, 20, 20}, {0, 0, 50, 30}, {0, 30, 50, 70}},
+		},
+		{
+			name:  "rows are bounded",
+			viewW: 400, viewH: 4000,
+			build: func() (*Container, []*Container) {
+				far := NewContainer(StaticPx(10), StaticPx(10))
+				far.Cell = &GridCell{Row: math.MaxInt32, RowSpan: math.MaxInt32}
+				g := NewContainer(StaticPx(100), FitSize(), far)
+				g.Grid = &Grid{Columns: EqualTracks(1), RowGap: 1}
+				return NewContainer(AutoSize(), AutoSize(), g), []*Container{far}
+			},
+			want: []Rect{{0, maxGridRows - 1, 10, 10}},
+		},
+	})
+}
+
+func TestFloating(t *testing.T) {
+	runLayoutCases(t, []layoutCase{
+		{
+			name:  "attached to the parent, out of the flow",
+			viewW: 200, viewH: 100,
+			build: func() (*Container, []*Container) {
+				pop := NewContainer(StaticPx(30), StaticPx(20))
+				pop.Floating = &Floating{Element: AttachTopLeft, Target: AttachBottomLeft, OffsetY: 2}
+				sibling := NewContainer(StaticPx(40), StaticPx(10))
+				anchor := NewContainer(StaticPx(50), StaticPx(20), pop, sibling)
+				anchor.Padding = PaddingAll(5)
+				root := NewContainer(AutoSize(), AutoSize(), NewContainer(AutoSize(), StaticPx(10)), anchor)
+				return root, []*Container{anchor, sibling, pop}
+			},
+			want: []Rect{{0, 10, 50, 20}, {5, 15, 40, 10}, {0, 32, 30, 20}},
+		},
+		{
+			name:  "clamped to the viewport",
+			viewW: 200, viewH: 100,
+			build: func() (*Container, []*Container) {
+				pop := NewContainer(StaticPx(50), StaticPx(20))
+				pop.Floating = &Floating{Element: AttachTopLeft, Target: AttachBottomRight, ClampToViewport: true}
+				return NewContainer(AutoSize(), AutoSize(), pop), []*Container{pop}
+			},
+			want: []Rect{{150, 80, 50, 20}},
+		},
+		{
+			name:  "FlipX attaches to the other side when it would overflow",
+			viewW: 200, viewH: 100,
+			build: func() (*Container, []*Container) {
+				pop := NewContainer(StaticPx(30), StaticPx(20))
+				pop.Floating = &Floating{Element: AttachTopLeft, Target: AttachTopRight, OffsetX: 2, FlipX: true}
+				anchor := NewContainer(StaticPx(40), StaticPx(30), pop)
+				root := row(AutoSize(), AutoSize(), NewContainer(StaticPx(150), AutoSize()), anchor)
+				return root, []*Container{anchor, pop}
+			},
+			want: []Rect{{150, 0, 40, 30}, {118, 0, 30, 20}},
+		},
+		{
+			name:  "FlipX keeps the side that fits",
+			viewW: 200, viewH: 100,
+			build: func() (*Container, []*Container) {
+				pop := NewContainer(StaticPx(30), StaticPx(20))
+				pop.Floating = &Floating{Element: AttachTopLeft, Target: AttachTopRight, OffsetX: 2, FlipX: true}
+				anchor := NewContainer(StaticPx(40), StaticPx(30), pop)
+				return row(AutoSize(), AutoSize(), anchor), []*Container{pop}
+			},
+			want: []Rect{{42, 0, 30, 20}},
+		},
+	})
+}
@@ -5,14 +5,19 @@ type Sizing int
 
 const (
 	Static  Sizing = iota // fixed pixels
-	Percent                 // percentage of parent (0–100)
-	Auto                    // fill remaining space
+	Percent               // percentage of parent (0–100)
+	Auto                  // fill remaining space
+	Fit                   // shrink-wrap children or intrinsic content
 )
 
-// Size specifies width or height: Static (px), Percent (0–100), or Auto.
+// Size specifies width or height: Static (px), Percent (0–100), Auto or Fit.
+// Min and Max clamp the resolved size in pixels; zero means no limit.
 type Size struct {
-	Kind  Sizing
-	Value float64 // pixels for Static, 0–100 for Percent; ignored for Auto
+	Kind   Sizing
+	Value  float64 // pixels for Static, 0–100 for Percent; ignored for Auto and Fit
+	Weight float64 // share of remaining space for Auto (0 counts as 1)
+	Min    float64
+	Max    float64
 }
 
 // Alignment controls child placement inside a container.
@@ -39,19 +44,183 @@ func AutoSize() Size {
 	return Size{Kind: Auto}
 }
 
+// Grow returns an auto size that takes weight parts of the remaining space,
+// e.g. Grow(1) and Grow(3) siblings split it 1:3.
+func Grow(weight float64) Size {
+	return Size{Kind: Auto, Weight: weight}
+}
+
+// FitSize returns a size computed from the container's children or intrinsic content.
+func FitSize() Size {
+	return Size{Kind: Fit}
+}
+
+// WithMin returns a copy of s that never resolves below px.
+func (s Size) WithMin(px float64) Size {
+	s.Min = px
+	return s
+}
+
+// WithMax returns a copy of s that never resolves above px.
+func (s Size) WithMax(px float64) Size {
+	s.Max = px
+	return s
+}
+
+// growWeight returns the Auto weight, defaulting to 1.
+func (s Size) growWeight() float64 {
+	if s.Weight <= 0 {
+		return 1
+	}
+	return s.Weight
+}
+
+// clamp applies Min and Max to a resolved size.
+func (s Size) clamp(v float64) float64 {
+	if s.Max > 0 && v > s.Max {
+		v = s.Max
+	}
+	if v < s.Min {
+		v = s.Min
+	}
+	return v
+}
+
+// Direction is the axis along which a container stacks its children.
+type Direction int
+
+const (
+	TopToBottom Direction = iota // children stacked vertically (default)
+	LeftToRight                  // children placed side by side
+)
+
+// Padding is the inner spacing between a container's edges and its children, per side in pixels.
+type Padding struct {
+	Left, Top, Right, Bottom float64
+}
+
+// PaddingAll returns the same padding on every side.
+func PaddingAll(px float64) Padding {
+	return Padding{Left: px, Top: px, Right: px, Bottom: px}
+}
+
+// PaddingXY returns horizontal (left/right) and vertical (top/bottom) padding.
+func PaddingXY(x, y float64) Padding {
+	return Padding{Left: x, Top: y, Right: x, Bottom: y}
+}
+
+// Overflow controls how a container treats children that extend past its bounds.
+type Overflow int
+
+const (
+	OverflowVisible Overflow = iota // children may draw outside the container (default)
+	OverflowClip                    // children are clipped to the container bounds
+	OverflowScroll                  // clipped, and shifted by ScrollX/ScrollY
+)
+
+// AttachPoint is one of nine anchor points on a rectangle.
+type AttachPoint int
+
+const (
+	AttachTopLeft AttachPoint = iota
+	AttachTopCenter
+	AttachTopRight
+	AttachCenterLeft
+	AttachCenter
+	AttachCenterRight
+	AttachBottomLeft
+	AttachBottomCenter
+	AttachBottomRight
+)
+
+// AttachTarget selects what a floating container is positioned against.
+type AttachTarget int
+
+const (
+	AttachToParent   AttachTarget = iota // the parent container's bounds
+	AttachToViewport                     // the whole layout viewport
+)
+
+// Floating takes a container out of its parent's flow and places it relative to an attach
+// point on its parent or on the viewport: the container's Element point is put on the
+// target's Target point, then moved by the offset. Floating containers do not take space
+// from their siblings, are not clipped by their ancestors, and Percent/Auto sizes resolve
+// against the target. ZIndex orders overlapping floating containers (higher is on top).
+type Floating struct {
+	AttachTo        AttachTarget
+	Element         AttachPoint
+	Target          AttachPoint
+	OffsetX         float64
+	OffsetY         float64
+	ZIndex          int
+	ClampToViewport bool // keep the container inside the viewport
+	FlipX           bool // attach to the mirrored side (e.g. left instead of right) when it would overflow the viewport horizontally
+}
+
 // Rect is the computed bounds (x, y, width, height) after layout.
 type Rect struct {
 	X, Y, W, H float64
 }
 
+// Intersect returns the overlap of r and o; the result has zero size when they do not overlap.
+func (r Rect) Intersect(o Rect) Rect {
+	x0, y0 := max(r.X, o.X), max(r.Y, o.Y)
+	x1, y1 := min(r.X+r.W, o.X+o.W), min(r.Y+r.H, o.Y+o.H)
+	if x1 <= x0 || y1 <= y0 {
+		return Rect{X: x0, Y: y0}
+	}
+	return Rect{X: x0, Y: y0, W: x1 - x0, H: y1 - y0}
+}
+
+// point returns the position of an attach point on r.
+func (r Rect) point(p AttachPoint) (x, y float64) {
+	col, row := int(p)%3, int(p)/3
+	return r.X + r.W*float64(col)/2, r.Y + r.H*float64(row)/2
+}
+
+// mirrorX returns the attach point on the opposite side horizontally (left <-> right).
+func (p AttachPoint) mirrorX() AttachPoint {
+	col, row := int(p)%3, int(p)/3
+	return AttachPoint(row*3 + 2 - col)
+}
+
+// Empty reports whether r has no area.
+func (r Rect) Empty() bool {
+	return r.W <= 0 || r.H <= 0
+}
+
+// MeasureFunc reports the intrinsic content size of a leaf (e.g. a label's text).
+// availW is the resolved width when known, or 0 while widths are being computed.
+type MeasureFunc func(availW float64) (w, h float64)
+
 // Container is a nested layout node. Width and Height define size; Bounds is filled by Layout.
+// Direction selects the main axis; HorizontalAlign and VerticalAlign apply to the group of
+// children along the main axis and to each child along the cross axis.
 type Container struct {
 	Width           Size
 	Height          Size
+	Direction       Direction
+	Padding         Padding
+	ChildGap        float64 // space between consecutive children along the main axis
+	Wrap            bool    // start a new line when children overflow the main axis
+	LineGap         float64 // space between wrapped lines along the cross axis
+	Overflow        Overflow
+	ScrollX         float64 // scroll offset for OverflowScroll; clamped by Layout
+	ScrollY         float64
 	HorizontalAlign Alignment
 	VerticalAlign   Alignment
 	Children        []*Container
-	Bounds          Rect // set by Layout (Pass 1 + Pass 2)
+	Floating        *Floating   // non-nil to lay this node out outside its parent's flow
+	Grid            *Grid       // non-nil to place children in grid cells instead of stacking
+	Cell            *GridCell   // cell inside a Grid parent; nil for automatic placement
+	Measure         MeasureFunc // intrinsic size used by Fit when there are no flow children
+	Bounds          Rect        // set by Layout (Pass 1 + Pass 2)
+	Clip            Rect        // area ancestors let this node draw in (Pass 2)
+	ContentW        float64     // extent of the children plus padding (Pass 2)
+	ContentH        float64
+
+	fitW, fitH float64    // content size computed bottom-up in Pass 1
+	grid       gridLayout // cells of the children of a Grid container, resolved in Pass 1
 }
 
 // NewContainer returns a container with optional children. Default size is Auto.
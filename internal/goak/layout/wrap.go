@@ -0,0 +1,150 @@
+package layout
+
+// lineRange is a run of consecutive flow children placed on one wrapped line.
+type lineRange struct {
+	start, end int
+}
+
+// wrapLines splits children with the given main-axis sizes into lines no longer than space.
+// A child that does not fit after the previous ones (plus gap) starts a new line; every line
+// holds at least one child.
+func wrapLines(sizes []float64, space, gap float64) []lineRange {
+	var lines []lineRange
+	start := 0
+	used := 0.0
+	for i, s := range sizes {
+		if i > start && used+gap+s > space+epsilon {
+			lines = append(lines, lineRange{start, i})
+			start = i
+			used = 0
+		}
+		if i > start {
+			used += gap
+		}
+		used += s
+	}
+	if start < len(sizes) {
+		lines = append(lines, lineRange{start, len(sizes)})
+	}
+	return lines
+}
+
+// mainExtents returns the current main-axis extent of each child.
+func mainExtents(children []*Container, a axis) []float64 {
+	sizes := make([]float64, len(children))
+	for i, child := range children {
+		sizes[i] = child.extent(a)
+	}
+	return sizes
+}
+
+// wrapFitHeight returns the content height of a LeftToRight wrapping container once its
+// width is resolved: the sum of line heights plus LineGap. Top-to-bottom wrapping has no
+// equivalent, as widths are resolved before heights.
+func wrapFitHeight(c *Container) float64 {
+	flow := c.flowChildren()
+	space := nonNegative(c.Bounds.W - c.padding(axisX))
+	lines := wrapLines(mainExtents(flow, axisX), space, c.ChildGap)
+	total := c.LineGap * float64(max(len(lines)-1, 0))
+	for _, line := range lines {
+		var lineH float64
+		for _, child := range flow[line.start:line.end] {
+			lineH = max(lineH, child.fitContribution(axisY))
+		}
+		total += lineH
+	}
+	return total
+}
+
+// sizeWrapMain sizes the children of a wrapping container along its main axis. Children
+// keep their own size (Auto counts as Fit) to form lines; Auto children then grow by weight
+// into the space left on their line.
+func sizeWrapMain(c *Container, a axis, content float64) {
+	flow := c.flowChildren()
+	sizes := make([]float64, len(flow))
+	frozen := make([]bool, len(flow))
+	for i, child := range flow {
+		s := child.size(a)
+		if s.Kind == Auto {
+			sizes[i] = s.clamp(child.fit(a))
+		} else {
+			sizes[i] = resolveSize(s, content, child.fit(a))
+			frozen[i] = true
+		}
+	}
+	for _, line := range wrapLines(sizes, content, c.ChildGap) {
+		used := c.ChildGap * float64(line.end-line.start-1)
+		for _, s := range sizes[line.start:line.end] {
+			used += s
+		}
+		if used < content {
+			grow(flow[line.start:line.end], a, sizes[line.start:line.end], frozen[line.start:line.end], content-used)
+		}
+	}
+	for i, child := range flow {
+		child.setExtent(a, sizes[i])
+	}
+}
+
+// sizeWrapCross sizes the children of a wrapping container along its cross axis.
+// Auto children take their content size rather than filling the whole container.
+func sizeWrapCross(c *Container, a axis, content float64) {
+	for _, child := range c.flowChildren() {
+		s := child.size(a)
+		if s.Kind == Auto {
+			child.setExtent(a, s.clamp(child.fit(a)))
+		} else {
+			child.setExtent(a, resolveSize(s, content, child.fit(a)))
+		}
+	}
+}
+
+// positionWrap places the children of a wrapping container line by line. Each line is
+// aligned along the main axis on its own, and children are aligned across the line.
+func positionWrap(c *Container, clip, view Rect) {
+	horizontal := c.Direction == LeftToRight
+	mainAxis, crossAxis := axisY, axisX
+	mainAlign, crossAlign := c.VerticalAlign, c.HorizontalAlign
+	if horizontal {
+		mainAxis, crossAxis = axisX, axisY
+		mainAlign, crossAlign = c.HorizontalAlign, c.VerticalAlign
+	}
+	boxMain := nonNegative(c.extent(mainAxis) - c.padding(mainAxis))
+
+	flow := c.flowChildren()
+	lines := wrapLines(mainExtents(flow, mainAxis), boxMain, c.ChildGap)
+	lineMain := make([]float64, len(lines))
+	lineCross := make([]float64, len(lines))
+	var extentMain float64
+	extentCross := c.LineGap * float64(max(len(lines)-1, 0))
+	for i, line := range lines {
+		lineMain[i] = c.ChildGap * float64(line.end-line.start-1)
+		for _, child := range flow[line.start:line.end] {
+			lineMain[i] += child.extent(mainAxis)
+			lineCross[i] = max(lineCross[i], child.extent(crossAxis))
+		}
+		extentMain = max(extentMain, lineMain[i])
+		extentCross += lineCross[i]
+	}
+
+	extentW, extentH := extentCross, extentMain
+	if horizontal {
+		extentW, extentH = extentMain, extentCross
+	}
+	ox, oy, childClip := c.contentOrigin(extentW, extentH, clip)
+
+	crossCursor := 0.0
+	for i, line := range lines {
+		cursor := AlignOffset(mainAlign, boxMain, lineMain[i])
+		for _, child := range flow[line.start:line.end] {
+			cross := crossCursor + AlignOffset(crossAlign, lineCross[i], child.extent(crossAxis))
+			cx, cy := ox+cross, oy+cursor
+			if horizontal {
+				cx, cy = ox+cursor, oy+cross
+			}
+			pass2Position(child, cx, cy, childClip, view)
+			cursor += child.extent(mainAxis) + c.ChildGap
+		}
+		crossCursor += lineCross[i] + c.LineGap
+	}
+}
@@ -0,0 +1,67 @@
+package rendering
+
+import (
+	"goak/internal/goak/layout"
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ClipStack restricts drawing to nested rectangles of a destination image.
+// Push returns a sub-image limited to the intersection of all pushed rects; sub-images keep
+// the destination's coordinates, so drawing code does not need to change.
+type ClipStack struct {
+	base  *ebiten.Image
+	rects []image.Rectangle
+}
+
+// NewClipStack returns a clip stack over dst with no clipping applied.
+func NewClipStack(dst *ebiten.Image) *ClipStack {
+	return &ClipStack{base: dst}
+}
+
+// Push narrows the clip to r and returns the image to draw into.
+func (s *ClipStack) Push(r layout.Rect) *ebiten.Image {
+	next := toPixelRect(r).Intersect(s.current())
+	s.rects = append(s.rects, next)
+	return s.Target()
+}
+
+// Pop restores the previous clip and returns the image to draw into.
+func (s *ClipStack) Pop() *ebiten.Image {
+	if len(s.rects) > 0 {
+		s.rects = s.rects[:len(s.rects)-1]
+	}
+	return s.Target()
+}
+
+// Target returns the image limited to the current clip.
+func (s *ClipStack) Target() *ebiten.Image {
+	if len(s.rects) == 0 {
+		return s.base
+	}
+	return s.base.SubImage(s.current()).(*ebiten.Image)
+}
+
+// Visible reports whether the current clip has any area left to draw into.
+func (s *ClipStack) Visible() bool {
+	return !s.current().Empty()
+}
+
+func (s *ClipStack) current() image.Rectangle {
+	if len(s.rects) == 0 {
+		return s.base.Bounds()
+	}
+	return s.rects[len(s.rects)-1]
+}
+
+// toPixelRect converts r to whole pixels, covering every partially visible pixel.
+func toPixelRect(r layout.Rect) image.Rectangle {
+	return image.Rect(
+		int(math.Floor(r.X)),
+		int(math.Floor(r.Y)),
+		int(math.Ceil(r.X+r.W)),
+		int(math.Ceil(r.Y+r.H)),
+	)
+}
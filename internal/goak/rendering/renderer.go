@@ -31,6 +31,11 @@ func DrawLine(dst *ebiten.Image, x, y, length, thickness float64, c colors.Color
 	}
 }
 
+// StrokeLine draws a straight line between two points.
+func StrokeLine(dst *ebiten.Image, x0, y0, x1, y1, thickness float64, c colors.Color) {
+	vector.StrokeLine(dst, float32(x0), float32(y0), float32(x1), float32(y1), float32(thickness), c, true)
+}
+
 // DrawFilledCircle draws a filled circle.
 func DrawFilledCircle(dst *ebiten.Image, centerX, centerY, radius float64, c colors.Color) {
 	vector.DrawFilledCircle(dst, float32(centerX), float32(centerY), float32(radius), c, true)
@@ -41,12 +46,27 @@ func DrawCircleStroke(dst *ebiten.Image, centerX, centerY, radius, thickness flo
 	vector.StrokeCircle(dst, float32(centerX), float32(centerY), float32(radius), float32(thickness), c, true)
 }
 
+// DrawImageFit draws img scaled to fit inside the rectangle, keeping its aspect ratio,
+// centered.
+func DrawImageFit(dst, img *ebiten.Image, x, y, w, h float64) {
+	iw, ih := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+	if iw <= 0 || ih <= 0 {
+		return
+	}
+	scale := min(w/iw, h/ih)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(x+(w-iw*scale)/2, y+(h-ih*scale)/2)
+	op.Filter = ebiten.FilterLinear
+	dst.DrawImage(img, op)
+}
+
 // DrawText renders text at the specified position.
-func DrawText(dst *ebiten.Image, str string, face text.GoTextFace, x, y int, c colors.Color) {
+func DrawText(dst *ebiten.Image, str string, face text.Face, x, y int, c colors.Color) {
 	op := &text.DrawOptions{}
 	op.GeoM.Translate(float64(x), float64(y))
 	op.ColorScale.ScaleWithColor(c)
-	text.Draw(dst, str, &face, op)
+	text.Draw(dst, str, face, op)
 }
 
 // PointWithinBounds returns true if the point (x, y) is inside the given rectangle.
@@ -1,7 +1,6 @@
 package goak
 
 import (
-	"bytes"
 	"goak/internal/goak/colors"
 	"goak/internal/goak/components"
 	"goak/internal/goak/layout"
@@ -27,10 +26,12 @@ type Window struct {
 	hoveredRect          layout.Rect
 	hasHoveredRect       bool
 
-	ui *components.UI
+	ui    *components.UI
+	keys  []ebiten.Key // reused input buffers
+	chars []rune
 
-	canvas     *ebiten.Image
-	fontSource *text.GoTextFaceSource
+	canvas *ebiten.Image
+	fonts  *components.FontRegistry
 }
 
 // Window config options
@@ -52,12 +53,19 @@ func InitWindow(title string, width, height int) *Window {
 	})
 }
 
+// Built-in font family loaded into every window's font registry, and its default size.
+const (
+	DefaultFontFamily = "M PLUS 1p"
+	DefaultFontSize   = 20
+)
+
 // Internal function to initialize window
 func newWindow(cfg Config) *Window {
-	fs, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
-	if err != nil {
+	registry := components.NewFontRegistry()
+	if err := registry.LoadBytes(DefaultFontFamily, components.WeightRegular, components.StyleNormal, fonts.MPlus1pRegular_ttf); err != nil {
 		log.Fatal("error loading font", err)
 	}
+	registry.SetDefault(components.Font{Family: DefaultFontFamily, Size: DefaultFontSize})
 
 	return &Window{
 		title:       cfg.Title,
@@ -65,12 +73,86 @@ func newWindow(cfg Config) *Window {
 		height:      cfg.Height,
 		autoDPI:     cfg.AutoDPI,
 		windowScale: normalizeScale(cfg.WindowScale),
-		fontSource:  fs,
+		fonts:       registry,
 	}
 }
 
 func (win *Window) attachUI(ui *components.UI) {
 	win.ui = ui
+	if ui.Fonts() == nil {
+		ui.SetFonts(win.fonts)
+	}
+	win.registerCommands()
+}
+
+// Names of the built-in window commands, registered on the accelerators of the UI when the
+// window runs it. Rebind or disable them with Accelerators.Bind, Unbind and UnbindCommand,
+// before or after Run.
+const (
+	CommandZoomIn      = "window.zoomIn"      // Ctrl+= (also with Shift) and Ctrl+NumpadAdd
+	CommandZoomOut     = "window.zoomOut"     // Ctrl+- and Ctrl+NumpadSubtract
+	CommandToggleDebug = "window.toggleDebug" // F12, with any modifiers
+)
+
+// Window scale range and step of the zoom commands.
+const (
+	minWindowScale  = 0.5
+	maxWindowScale  = 4.0
+	windowScaleStep = 0.1
+)
+
+// registerCommands registers the built-in window commands with their default keys. The
+// zoom commands are enabled while scale hotkeys are (see SetScaleHotkeysEnabled).
+func (win *Window) registerCommands() {
+	acc := win.ui.Accelerators()
+	register := func(c components.Command, keys ...string) {
+		if err := acc.Register(c, keys...); err != nil {
+			log.Printf("goak: %v", err)
+		}
+	}
+	scaleHotkeys := func() bool { return win.scaleHotkeys }
+	register(components.Command{
+		Name:    CommandZoomIn,
+		Run:     func() { win.stepWindowScale(windowScaleStep) },
+		Enabled: scaleHotkeys,
+	}, "Ctrl+=", "Ctrl+Shift+=", "Ctrl+NumpadAdd")
+	register(components.Command{
+		Name:    CommandZoomOut,
+		Run:     func() { win.stepWindowScale(-windowScaleStep) },
+		Enabled: scaleHotkeys,
+	}, "Ctrl+-", "Ctrl+NumpadSubtract")
+	register(components.Command{
+		Name: CommandToggleDebug,
+		Run:  func() { win.debugMode = !win.debugMode },
+	}, withAnyModifiers("F12")...)
+}
+
+// withAnyModifiers returns key alone and with every combination of modifiers, so that a
+// command runs whichever modifiers are held, e.g. F12, Shift+F12 and Ctrl+Alt+F12.
+func withAnyModifiers(key string) []string {
+	mods := []string{"Ctrl+", "Alt+", "Shift+", "Meta+"}
+	keys := make([]string, 0, 1<<len(mods))
+	for set := range 1 << len(mods) {
+		prefix := ""
+		for i, m := range mods {
+			if set&(1<<i) != 0 {
+				prefix += m
+			}
+		}
+		keys = append(keys, prefix+key)
+	}
+	return keys
+}
+
+// Fonts returns the window's font registry. Load fonts and define named fonts on it
+// before Run; the UI uses it unless it was given its own with UI.SetFonts.
+func (win *Window) Fonts() *components.FontRegistry {
+	return win.fonts
+}
+
+// textFace returns the face of the UI default font, used for the debug overlay.
+func (win *Window) textFace() text.Face {
+	return win.ui.Face()
 }
 
 func (win *Window) SetTitle(title string) {
@@ -106,7 +188,8 @@ func (win *Window) WindowScale() float64 {
 	return win.windowScale
 }
 
-// SetScaleHotkeysEnabled toggles built-in Ctrl +/- scale shortcuts.
+// SetScaleHotkeysEnabled toggles built-in Ctrl +/- scale shortcuts (the CommandZoomIn and
+// CommandZoomOut accelerators).
 func (win *Window) SetScaleHotkeysEnabled(enabled bool) {
 	win.scaleHotkeys = enabled
 }
@@ -141,132 +224,75 @@ func (win *Window) Update() error {
 		return nil
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyF12) {
-		win.debugMode = !win.debugMode
-	}
-
-	if win.scaleHotkeys {
-		win.handleScaleHotkeys()
-	}
-
 	logicalW, logicalH := win.logicalScreenSize()
 
 	root := win.ui.Root()
 	uiScale := win.effectiveUIScale(root.Scale)
 
-	for _, m := range win.ui.MenuBars() {
-		m.SyncWidth()
-	}
-
-	layout.Layout(root.Container(), float64(logicalW)/uiScale, float64(logicalH)/uiScale)
-
 	mx, my := ebiten.CursorPosition()
 	lx := float64(mx) / uiScale
 	ly := float64(my) / uiScale
-	for _, m := range win.ui.MenuBars() {
-		m.OnMouseMove(lx, ly)
-	}
+	mods := currentModifiers()
+	win.ui.Dispatch(&components.Event{Type: components.EventMouseMove, X: lx, Y: ly, Mods: mods})
 	win.updateHoveredElement(lx, ly)
+	win.handleWheel(lx, ly, mods)
 
-	for _, s := range win.ui.Sliders() {
-		if s.IsDragging() {
-			s.UpdateValue(lx)
+	for _, button := range []ebiten.MouseButton{ebiten.MouseButtonLeft, ebiten.MouseButtonRight, ebiten.MouseButtonMiddle} {
+		if inpututil.IsMouseButtonJustPressed(button) {
+			win.ui.Dispatch(&components.Event{Type: components.EventMouseDown, X: lx, Y: ly, Button: button, Mods: mods})
+		}
+		if inpututil.IsMouseButtonJustReleased(button) {
+			win.ui.Dispatch(&components.Event{Type: components.EventMouseUp, X: lx, Y: ly, Button: button, Mods: mods})
 		}
 	}
+	win.dispatchKeys(mods)
 
-	for _, rg := range win.ui.RadioGroups() {
-		hitIndex := rg.HitTest(lx, ly)
-		rg.SetHovered(hitIndex)
-	}
+	// Input above is hit-tested against the layout drawn last frame; lay out again so
+	// state changed this frame (opened popups, scrolling, resizes) shows up in Draw.
+	win.ui.Layout(float64(logicalW)/uiScale, float64(logicalH)/uiScale)
 
-	for _, dd := range win.ui.Dropdowns() {
-		if dd.IsOpen() {
-			hitIndex := dd.HitTestList(lx, ly)
-			dd.SetHovered(hitIndex)
-		}
-	}
+	return nil
+}
 
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		consumed := false
-		for _, m := range win.ui.MenuBars() {
-			if m.OnMouseDown(lx, ly) {
-				consumed = true
-				break
-			}
-		}
-		if !consumed {
-			for i, b := range win.ui.Buttons() {
-				bound := b.Bounds()
-				if lx >= bound.X && lx < bound.X+bound.W && ly >= bound.Y && ly < bound.Y+bound.H {
-					win.ui.ButtonClicked(i)
-					consumed = true
-					break
-				}
-			}
-		}
-		if !consumed {
-			for _, cb := range win.ui.Checkboxes() {
-				if rendering.PointWithinBounds(lx, ly, cb.Bounds()) {
-					cb.Toggle()
-					consumed = true
-					break
-				}
-			}
-		}
-		if !consumed {
-			for _, rg := range win.ui.RadioGroups() {
-				hitIndex := rg.HitTest(lx, ly)
-				if hitIndex >= 0 {
-					rg.Select(hitIndex)
-					consumed = true
-					break
-				}
-			}
-		}
-		if !consumed {
-			for _, s := range win.ui.Sliders() {
-				if rendering.PointWithinBounds(lx, ly, s.Bounds()) {
-					s.StartDrag()
-					s.UpdateValue(lx)
-					consumed = true
-					break
-				}
-			}
-		}
-		if !consumed {
-			for _, dd := range win.ui.Dropdowns() {
-				if dd.IsOpen() {
-					hitIndex := dd.HitTestList(lx, ly)
-					if hitIndex >= 0 {
-						dd.Select(hitIndex)
-						consumed = true
-						break
-					}
-					// Close dropdown if clicked outside the list
-					listBounds := dd.ListBounds()
-					if !rendering.PointWithinBounds(lx, ly, listBounds) {
-						dd.Close()
-						consumed = true
-						break
-					}
-				} else if rendering.PointWithinBounds(lx, ly, dd.Bounds()) {
-					dd.Open()
-					consumed = true
-					break
-				}
-			}
-		}
+// handleWheel sends wheel motion to the widget under the cursor; it bubbles up to the
+// innermost scroll panel that can scroll in that direction. Shift turns vertical wheel
+// motion into horizontal scrolling.
+func (win *Window) handleWheel(x, y float64, mods components.Modifiers) {
+	dx, dy := ebiten.Wheel()
+	if dx == 0 && dy == 0 {
+		return
 	}
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		dx, dy = dy, 0
+	}
+	win.ui.Dispatch(&components.Event{Type: components.EventWheel, X: x, Y: y, WheelX: dx, WheelY: dy, Mods: mods})
+}
 
-	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-		for _, s := range win.ui.Sliders() {
-			if s.IsDragging() {
-				s.StopDrag()
-			}
+// Held keys repeat after keyRepeatDelay ticks, every keyRepeatInterval ticks.
+const (
+	keyRepeatDelay    = 30
+	keyRepeatInterval = 3
+)
+
+// dispatchKeys sends this frame's key presses (and repeats), releases and typed characters
+// to the UI.
+func (win *Window) dispatchKeys(mods components.Modifiers) {
+	win.keys = inpututil.AppendPressedKeys(win.keys[:0])
+	for _, k := range win.keys {
+		d := inpututil.KeyPressDuration(k)
+		repeat := d > keyRepeatDelay && (d-keyRepeatDelay)%keyRepeatInterval == 0
+		if d == 1 || repeat {
+			win.ui.Dispatch(&components.Event{Type: components.EventKeyDown, Key: k, Repeat: repeat, Mods: mods})
 		}
 	}
-
-	return nil
+	win.keys = inpututil.AppendJustReleasedKeys(win.keys[:0])
+	for _, k := range win.keys {
+		win.ui.Dispatch(&components.Event{Type: components.EventKeyUp, Key: k, Mods: mods})
+	}
+	win.chars = ebiten.AppendInputChars(win.chars[:0])
+	if len(win.chars) > 0 {
+		win.ui.Dispatch(&components.Event{Type: components.EventTextInput, Text: string(win.chars), Mods: mods})
+	}
 }
 
 func (win *Window) Draw(screen *ebiten.Image) {
@@ -325,65 +351,18 @@ func (win *Window) drawUI(dst *ebiten.Image) {
 	logicalW := float64(screenW)
 	logicalH := float64(screenH)
 
-	bg := colors.Black
-	dst.Fill(bg)
-
-	panelTheme := components.DefaultPanelTheme()
-	buttonTheme := components.DefaultButtonTheme()
-	menuTheme := components.DefaultMenuTheme()
-
-	face := text.GoTextFace{
-		Source: win.fontSource,
-		Size:   20,
-	}
-
-	for _, p := range win.ui.Panels() {
-		p.Draw(dst, panelTheme)
-	}
-
-	for _, b := range win.ui.Buttons() {
-		b.Draw(dst, face, buttonTheme)
-	}
-
-	checkboxTheme := components.DefaultCheckboxTheme()
-	for _, cb := range win.ui.Checkboxes() {
-		cb.Draw(dst, face, checkboxTheme, false)
-	}
-
-	radioTheme := components.DefaultRadioTheme()
-	for _, rg := range win.ui.RadioGroups() {
-		rg.Draw(dst, face, radioTheme)
-	}
-
-	sliderTheme := components.DefaultSliderTheme()
-	for _, s := range win.ui.Sliders() {
-		s.Draw(dst, face, sliderTheme)
-	}
-
-	dropdownTheme := components.DefaultDropdownTheme()
-	for _, dd := range win.ui.Dropdowns() {
-		dd.Draw(dst, face, dropdownTheme)
-	}
-
-	for _, m := range win.ui.MenuBars() {
-		m.DrawBar(dst, face, menuTheme)
-	}
-
-	for _, m := range win.ui.MenuBars() {
-		m.DrawDropdown(dst, face, menuTheme)
-	}
+	dst.Fill(win.ui.Theme().Background)
 
-	contextMenuTheme := components.DefaultContextMenuTheme()
-	for _, cm := range win.ui.ContextMenus() {
-		cm.Draw(dst, face, contextMenuTheme)
-	}
+	face := win.textFace()
+	win.ui.Draw(dst)
+	win.drawFocusRing(dst)
 
 	if win.debugMode {
 		if win.hasHoveredRect {
 			rendering.DrawStrokeRect(dst, win.hoveredRect.X, win.hoveredRect.Y, win.hoveredRect.W, win.hoveredRect.H, 2.0, colors.Yellow)
 		}
 		const label = "Debug Mode"
-		lw, lh := text.Measure(label, &face, 0)
+		lw, lh := text.Measure(label, face, 0)
 		const margin = 8.0
 		x := logicalW - lw - margin
 		y := logicalH - lh - margin
@@ -446,28 +425,9 @@ func (win *Window) logicalScreenSize() (int, int) {
 	return int(math.Ceil(float64(w) * dpi)), int(math.Ceil(float64(h) * dpi))
 }
 
-func (win *Window) handleScaleHotkeys() {
-	if !isCtrlPressed() {
-		return
-	}
-	step := 0.1
-	minScale := 0.5
-	maxScale := 4.0
-	cur := win.WindowScale()
-	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyKPAdd) {
-		next := cur + step
-		if next > maxScale {
-			next = maxScale
-		}
-		win.SetWindowScale(next)
-	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyKPSubtract) {
-		next := cur - step
-		if next < minScale {
-			next = minScale
-		}
-		win.SetWindowScale(next)
-	}
+// stepWindowScale changes the window scale by step, within the zoom range.
+func (win *Window) stepWindowScale(step float64) {
+	win.SetWindowScale(min(max(win.WindowScale()+step, minWindowScale), maxWindowScale))
 }
 
 func isCtrlPressed() bool {
@@ -476,6 +436,24 @@ func isCtrlPressed() bool {
 		ebiten.IsKeyPressed(ebiten.KeyControlRight)
 }
 
+// currentModifiers returns the modifier keys held right now.
+func currentModifiers() components.Modifiers {
+	var mods components.Modifiers
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		mods |= components.ModShift
+	}
+	if isCtrlPressed() {
+		mods |= components.ModCtrl
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyAlt) {
+		mods |= components.ModAlt
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyMeta) {
+		mods |= components.ModMeta
+	}
+	return mods
+}
+
 func normalizeScale(v float64) float64 {
 	if v <= 0 {
 		return 1
@@ -499,52 +477,27 @@ func (win *Window) updateHoveredElement(x, y float64) {
 	if !win.debugMode || win.ui == nil {
 		return
 	}
-
-	menus := win.ui.MenuBars()
-	for i := len(menus) - 1; i >= 0; i-- {
-		m := menus[i]
-		if m.IsOpen() {
-			subRects := m.OpenSubItemRects()
-			for j := len(subRects) - 1; j >= 0; j-- {
-				if rendering.PointWithinBounds(x, y, subRects[j]) {
-					win.hoveredRect = subRects[j]
-					win.hasHoveredRect = true
-					return
-				}
-			}
-		}
-	}
-	for i := len(menus) - 1; i >= 0; i-- {
-		m := menus[i]
-		topRects := m.TopItemRects()
-		for j := len(topRects) - 1; j >= 0; j-- {
-			if rendering.PointWithinBounds(x, y, topRects[j]) {
-				win.hoveredRect = topRects[j]
-				win.hasHoveredRect = true
-				return
-			}
-		}
-		if rendering.PointWithinBounds(x, y, m.Bounds()) {
-			win.hoveredRect = m.Bounds()
-			win.hasHoveredRect = true
-			return
-		}
+	w := win.ui.WidgetAt(x, y)
+	if _, isRoot := w.(*components.Root); isRoot {
+		return
 	}
+	win.hoveredRect = w.Container().Bounds
+	win.hasHoveredRect = true
+}
 
-	buttons := win.ui.Buttons()
-	for i := len(buttons) - 1; i >= 0; i-- {
-		if rendering.PointWithinBounds(x, y, buttons[i].Bounds()) {
-			win.hoveredRect = buttons[i].Bounds()
-			win.hasHoveredRect = true
-			return
-		}
+// drawFocusRing outlines the focused widget when focus was moved with the keyboard.
+func (win *Window) drawFocusRing(dst *ebiten.Image) {
+	if !win.ui.FocusVisible() {
+		return
 	}
-	panels := win.ui.Panels()
-	for i := len(panels) - 1; i >= 0; i-- {
-		if rendering.PointWithinBounds(x, y, panels[i].Bounds()) {
-			win.hoveredRect = panels[i].Bounds()
-			win.hasHoveredRect = true
-			return
-		}
+	theme := win.ui.FocusTheme()
+	c := win.ui.Focused().Container()
+	clips := rendering.NewClipStack(dst)
+	target := clips.Push(c.Clip)
+	if clips.Visible() {
+		b := c.Bounds
+		o := theme.Offset
+		rendering.DrawStrokeRect(target, b.X-o, b.Y-o, b.W+2*o, b.H+2*o, theme.Width, theme.Ring)
 	}
+	clips.Pop()
 }